@@ -0,0 +1,58 @@
+package xsql
+
+import (
+	"reflect"
+	"time"
+)
+
+// Clock provides current time for columns declared as `autoCreateTime` or `autoUpdateTime`.
+// It can be replaced by DbOption.Clock, e.g. for deterministic tests
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the default Clock which returns time.Now
+type SystemClock struct {
+}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fillTimestamps sets current time into fields of given struct value which are declared as
+// `autoCreateTime` or `autoUpdateTime`. If creating is true, both kinds of field are set if
+// they are zero. If updating is true, `autoUpdateTime` fields are set regardless of their values.
+// Supported field types are time.Time and *time.Time
+func (c *Client) fillTimestamps(val reflect.Value, creating, updating bool) {
+	var now time.Time
+	for _, f := range getMapping(val.Type()).fields {
+		autoCreate := f.options.has("autoCreateTime")
+		autoUpdate := f.options.has("autoUpdateTime")
+		if !autoCreate && !autoUpdate {
+			continue
+		}
+		field := f.value(val)
+		switch {
+		case updating && autoUpdate:
+		case creating && field.IsZero():
+		default:
+			continue
+		}
+		if now.IsZero() {
+			now = c.clock.Now()
+		}
+		setTime(field, now)
+	}
+}
+
+// addressable returns given value if it is addressable, otherwise it returns an addressable copy
+func addressable(val reflect.Value) reflect.Value {
+	if val.CanAddr() {
+		return val
+	}
+	cp := reflect.New(val.Type()).Elem()
+	cp.Set(val)
+	return cp
+}
@@ -1,8 +1,10 @@
 package xsql
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 )
 
 // strRepeat create a new string with repated pattern
@@ -26,20 +28,190 @@ func strRepeat(begin, end, pattern, sep string, size int) string {
 	return b.String()
 }
 
+// tagOptions is the list of options following column name in `column` tag,
+// e.g. `column:"id,pk"`
+type tagOptions []string
+
+// has reports whether given option is declared
+func (o tagOptions) has(option string) bool {
+	for _, v := range o {
+		if strings.EqualFold(v, option) {
+			return true
+		}
+	}
+	return false
+}
+
+// insertable reports whether column can be written by INSERT statement
+func (o tagOptions) insertable() bool {
+	return !o.has("readonly") && !o.has("noinsert")
+}
+
+// updatable reports whether column can be written by UPDATE statement
+func (o tagOptions) updatable() bool {
+	return !o.has("readonly") && !o.has("noupdate") && !o.has("softdelete")
+}
+
+// omitEmpty reports whether column is omitted from INSERT statement if its value is zero
+func (o tagOptions) omitEmpty() bool {
+	return o.has("omitempty") || o.has("default") || o.has("autoincrement") || o.has("softdelete")
+}
+
+// parseTag splits `column` tag into column name and its options
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	var options tagOptions
+	for _, option := range parts[1:] {
+		option = strings.TrimSpace(option)
+		if option != "" {
+			options = append(options, option)
+		}
+	}
+	return strings.TrimSpace(parts[0]), options
+}
+
+// fieldMapping is the mapping between a column and a field of struct
+type fieldMapping struct {
+	column  string
+	name    string
+	typ     reflect.Type
+	index   []int
+	options tagOptions
+}
+
+// value returns the field of given struct value by its index path. Nil pointers
+// to embedded structs on the path are allocated if they can be set, otherwise
+// zero value of field is returned
+func (f *fieldMapping) value(val reflect.Value) reflect.Value {
+	for i, x := range f.index {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				if !val.CanSet() {
+					return reflect.Zero(f.typ)
+				}
+				val.Set(reflect.New(val.Type().Elem()))
+			}
+			val = val.Elem()
+		}
+		val = val.Field(x)
+	}
+	return val
+}
+
+// typeMapping is the precomputed mapping between columns and fields of a struct type
+type typeMapping struct {
+	typ reflect.Type
+	// fields are in order of declaration
+	fields   []*fieldMapping
+	byColumn map[string]*fieldMapping
+	// byLowerColumn maps lower-case columns to fields, it is nil
+	// if many columns have the same lower-case name
+	byLowerColumn map[string]*fieldMapping
+	pk            []*fieldMapping
+	softDelete    *fieldMapping
+	version       *fieldMapping
+	mapper        ResultMapper
+	// err is the error found while building mapping, e.g. conflicting columns
+	err error
+}
+
+// mappings caches typeMapping of struct types
+var mappings sync.Map
+
+// getMapping returns the cached mapping of given reflect.Type, it is built at the first use
+func getMapping(t reflect.Type) *typeMapping {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if m, ok := mappings.Load(t); ok {
+		return m.(*typeMapping)
+	}
+	m, _ := mappings.LoadOrStore(t, newTypeMapping(t))
+	return m.(*typeMapping)
+}
+
+// newTypeMapping builds mapping of given struct type
+func newTypeMapping(t reflect.Type) *typeMapping {
+	tm := &typeMapping{
+		typ:           t,
+		byColumn:      make(map[string]*fieldMapping),
+		byLowerColumn: make(map[string]*fieldMapping),
+	}
+	if t.Kind() == reflect.Struct {
+		recursiveScan(t, nil, tm)
+		tm.resolveConflicts()
+	}
+	for _, f := range tm.fields {
+		lower := strings.ToLower(f.column)
+		if _, ok := tm.byLowerColumn[lower]; ok {
+			tm.byLowerColumn[lower] = nil
+		} else {
+			tm.byLowerColumn[lower] = f
+		}
+		if f.options.has("pk") {
+			tm.pk = append(tm.pk, f)
+		}
+		for _, option := range []string{"softdelete", "autoCreateTime", "autoUpdateTime"} {
+			if f.options.has(option) && !isTimeType(f.typ) && tm.err == nil {
+				tm.err = fmt.Errorf(`column %s of %s is declared as %s but its type %s is neither time.Time nor *time.Time`,
+					f.column, t, option, f.typ)
+			}
+		}
+		if f.options.has("softdelete") && tm.softDelete == nil {
+			tm.softDelete = f
+		}
+		if f.options.has("version") && tm.version == nil {
+			tm.version = f
+		}
+	}
+	if len(tm.pk) == 0 {
+		// column id is matched regardless of case, so that untagged fields ID or Id are found
+		if f, ok := tm.byColumn["id"]; ok {
+			tm.pk = []*fieldMapping{f}
+		} else if f := tm.byLowerColumn["id"]; f != nil {
+			tm.pk = []*fieldMapping{f}
+		}
+	}
+	tm.mapper = ResultMapper{
+		Type:      t,
+		Col2Field: make(map[string]string, len(tm.fields)),
+		Field2Col: make(map[string]string, len(tm.fields)),
+		mapping:   tm,
+	}
+	for _, f := range tm.fields {
+		tm.mapper.Col2Field[f.column] = f.name
+		tm.mapper.Field2Col[f.name] = f.column
+	}
+	return tm
+}
+
 // recursiveScan is a recursive action which tries to scan all fields
-// from an interface for building map between column and field name
-func recursiveScan(v reflect.Type, fields map[string]string) {
+// from a struct type for building mapping between column and field.
+// Fields of embedded struct are appended with index path started by given prefix.
+// Fields mapped to the same column are resolved later by resolveConflicts.
+func recursiveScan(v reflect.Type, prefix []int, tm *typeMapping) {
 	for i := 0; i < v.NumField(); i++ {
-		column := v.Field(i).Tag.Get("column")
-		if column == "-" {
+		column, opts := parseTag(v.Field(i).Tag.Get("column"))
+		if column == "-" || v.Field(i).Type == trackerType {
 			continue
 		}
+		index := make([]int, len(prefix)+1)
+		copy(index, prefix)
+		index[len(prefix)] = i
 
 		if column == "__embedded" {
+			// fields of unexported embedded pointers can not be set since such pointers can not
+			// be allocated, they are rejected as encoding/json does. So are unexported named fields
+			if !v.Field(i).IsExported() && (!v.Field(i).Anonymous || v.Field(i).Type.Kind() == reflect.Ptr) {
+				if tm.err == nil {
+					tm.err = fmt.Errorf(`embedded field %s of %s must be exported`, tm.fieldPath(index), tm.typ)
+				}
+				continue
+			}
 			if v.Field(i).Type.Kind() == reflect.Struct {
-				recursiveScan(v.Field(i).Type, fields)
+				recursiveScan(v.Field(i).Type, index, tm)
 			} else if v.Field(i).Type.Kind() == reflect.Ptr {
-				recursiveScan(v.Field(i).Type.Elem(), fields)
+				recursiveScan(v.Field(i).Type.Elem(), index, tm)
 			}
 			continue
 		}
@@ -49,42 +221,212 @@ func recursiveScan(v reflect.Type, fields map[string]string) {
 			column = fieldName
 		}
 
-		fields[column] = fieldName
+		tm.fields = append(tm.fields, &fieldMapping{
+			column:  column,
+			name:    fieldName,
+			typ:     v.Field(i).Type,
+			index:   index,
+			options: opts,
+		})
+	}
+}
+
+// resolveConflicts keeps only the shallowest field of each column, which shadows the others
+// as Go does for promoted fields. Fields at the same depth are conflicting. Since all fields
+// are scanned before resolving, the result does not depend on order of declaration
+func (tm *typeMapping) resolveConflicts() {
+	for _, f := range tm.fields {
+		if old, ok := tm.byColumn[f.column]; !ok || len(f.index) < len(old.index) {
+			tm.byColumn[f.column] = f
+		}
+	}
+	fields := make([]*fieldMapping, 0, len(tm.byColumn))
+	for _, f := range tm.fields {
+		shallowest := tm.byColumn[f.column]
+		switch {
+		case f == shallowest:
+			fields = append(fields, f)
+		case len(f.index) == len(shallowest.index) && tm.err == nil:
+			tm.err = fmt.Errorf(`column %s is mapped to both fields %s and %s of %s`,
+				f.column, tm.fieldPath(shallowest.index), tm.fieldPath(f.index), tm.typ)
+		}
+	}
+	tm.fields = fields
+}
+
+// fieldPath returns the selector of field at given index path, e.g. BaseModel.Id
+func (tm *typeMapping) fieldPath(index []int) string {
+	names := make([]string, len(index))
+	t := tm.typ
+	for i, x := range index {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		names[i] = t.Field(x).Name
+		t = t.Field(x).Type
+	}
+	return strings.Join(names, ".")
+}
+
+// lookup returns the field mapped to given column of query result by given policy.
+// It returns nil if column is not mapped but it can be ignored
+func (tm *typeMapping) lookup(column string, policy MappingPolicy) (*fieldMapping, error) {
+	f, ok := tm.byColumn[column]
+	if !ok && policy&MappingCaseInsensitive != 0 {
+		f, ok = tm.byLowerColumn[strings.ToLower(column)]
+		if ok && f == nil {
+			return nil, fmt.Errorf(`column %s matches many fields of %s regardless of case`, column, tm.typ)
+		}
+	}
+	if !ok {
+		if policy&MappingIgnoreUnknown != 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(`no such field mapped to column %s`, column)
 	}
+	return f, nil
 }
 
 // getMapper returns ResultMapper of given reflect.Type
-func getMapper(t reflect.Type) (rm ResultMapper) {
-	rm.Type = t
-	if rm.Type.Kind() == reflect.Ptr {
-		rm.Type = rm.Type.Elem()
+func getMapper(t reflect.Type) ResultMapper {
+	return getMapping(t).mapper
+}
+
+// mapper returns ResultMapper of given reflect.Type which matches columns by mapping policy of client
+func (c *Client) mapper(t reflect.Type) ResultMapper {
+	rm := getMapper(t)
+	rm.policy = c.mappingPolicy
+	return rm
+}
+
+// typeMapping returns the mapping which ResultMapper is built from
+func (rm ResultMapper) typeMapping() *typeMapping {
+	if rm.mapping == nil {
+		return getMapping(rm.Type)
+	}
+	return rm.mapping
+}
+
+// scanArgs returns pointers to fields of elem which are mapped to given columns
+func (rm ResultMapper) scanArgs(elem reflect.Value, cols []string) ([]interface{}, error) {
+	tm := rm.typeMapping()
+	if tm.err != nil {
+		return nil, tm.err
 	}
-	m := make(map[string]string)
-	recursiveScan(rm.Type, m)
-	rm.Col2Field = m
-	rm.Field2Col = make(map[string]string)
-	for c, f := range m {
-		rm.Field2Col[f] = c
+	args := make([]interface{}, len(cols))
+	for i, v := range cols {
+		f, err := tm.lookup(v, rm.policy)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			// value of ignored column is discarded
+			args[i] = new(interface{})
+			continue
+		}
+		field := f.value(elem)
+		if !field.CanAddr() {
+			return nil, fmt.Errorf(`field %s of %s can not be set`, tm.fieldPath(f.index), tm.typ)
+		}
+		args[i] = field.Addr().Interface()
 	}
-	return
+	return args, nil
 }
 
-// getColumnsAndFielNames returns columns amd fields of reflect.Type
-func getColumnsAndFielNames(valType reflect.Type) ([]string, []string) {
-	if valType.Kind() == reflect.Ptr {
-		valType = valType.Elem()
+// getColumnsAndFielNames returns columns amd fields of reflect.Type in order of declaration
+func getColumnsAndFielNames(valType reflect.Type) ([]string, []string, error) {
+	tm := getMapping(valType)
+	if tm.err != nil {
+		return nil, nil, tm.err
 	}
-	m := make(map[string]string)
-	recursiveScan(valType, m)
-	columns := make([]string, len(m))
-	fieldNames := make([]string, len(m))
-	i := 0
-	for col, field := range m {
-		columns[i] = col
-		fieldNames[i] = field
-		i++
+	columns := make([]string, len(tm.fields))
+	fieldNames := make([]string, len(tm.fields))
+	for i, f := range tm.fields {
+		columns[i] = f.column
+		fieldNames[i] = f.name
+	}
+	return columns, fieldNames, nil
+}
+
+// columnsOf returns columns of given fields
+func columnsOf(fields []*fieldMapping) []string {
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
 	}
-	return columns, fieldNames
+	return columns
+}
+
+// insertColumns returns columns and fields of given struct value which are written by INSERT statement
+func insertColumns(val reflect.Value) ([]string, []*fieldMapping, error) {
+	tm := getMapping(val.Type())
+	if tm.err != nil {
+		return nil, nil, tm.err
+	}
+	var columns []string
+	var fields []*fieldMapping
+	for _, f := range tm.fields {
+		if !f.options.insertable() {
+			continue
+		}
+		if f.options.omitEmpty() && f.value(val).IsZero() {
+			continue
+		}
+		columns = append(columns, f.column)
+		fields = append(fields, f)
+	}
+	return columns, fields, nil
+}
+
+// getPrimaryKeys returns columns and fields of primary key of reflect.Type.
+// Primary key is declared by `pk` option, e.g. `column:"id,pk"`. Many fields
+// can be declared as a composite key. If there is no declared field, the field
+// which is mapped to column `id` regardless of case is used, e.g. an untagged field ID.
+func getPrimaryKeys(t reflect.Type) ([]string, []*fieldMapping, error) {
+	tm := getMapping(t)
+	if tm.err != nil {
+		return nil, nil, tm.err
+	}
+	if len(tm.pk) == 0 {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		return nil, nil, fmt.Errorf(`model %s does not have primary key`, t)
+	}
+	return columnsOf(tm.pk), tm.pk, nil
+}
+
+// keyValues converts given id into values of primary key columns. If there are
+// many primary key columns, id must be a slice or an array of their values in order
+func keyValues(id interface{}, numberOfKeys int) ([]interface{}, error) {
+	if numberOfKeys == 1 {
+		return []interface{}{id}, nil
+	}
+	val := reflect.ValueOf(id)
+	if val.Kind() != reflect.Array && val.Kind() != reflect.Slice {
+		return nil, fmt.Errorf(`id of composite key must be either array or slice`)
+	}
+	if val.Len() != numberOfKeys {
+		return nil, fmt.Errorf(`number of id values and number of key columns does not match`)
+	}
+	rs := make([]interface{}, numberOfKeys)
+	for i := range rs {
+		rs[i] = val.Index(i).Interface()
+	}
+	return rs, nil
+}
+
+// keyCondition returns the condition matching given key columns, together with
+// its parameters, e.g. `k1 = :xsql_pk_0 AND k2 = :xsql_pk_1`
+func keyCondition(pkColumns []string, values []interface{}) (string, map[string]interface{}) {
+	conds := make([]string, len(pkColumns))
+	params := make(map[string]interface{}, len(pkColumns))
+	for i, column := range pkColumns {
+		key := fmt.Sprintf(`xsql_pk_%d`, i)
+		conds[i] = fmt.Sprintf(`%s = :%s`, column, key)
+		params[key] = values[i]
+	}
+	return strings.Join(conds, " AND "), params
 }
 
 // getTableName returns name of corresponding table of value of given interface
@@ -102,6 +444,25 @@ func getTableName(val reflect.Value) string {
 	}
 }
 
+// overflowInt reports whether i can not be represented by given integer field
+func overflowInt(field reflect.Value, i int64) bool {
+	switch field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return i < 0 || field.OverflowUint(uint64(i))
+	}
+	return field.OverflowInt(i)
+}
+
+// setInt sets given integer into a field of either signed or unsigned integer kind
+func setInt(field reflect.Value, i int64) {
+	switch field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(uint64(i))
+	default:
+		field.SetInt(i)
+	}
+}
+
 // strToIntf converts given slice string to slice interface
 func strToIntf(s []string) []interface{} {
 	b := make([]interface{}, len(s))
@@ -113,22 +474,26 @@ func strToIntf(s []string) []interface{} {
 
 // chunk splits a huge set into many smaller sets
 func chunk(list reflect.Value, size int) [][]reflect.Value {
+	values := make([]reflect.Value, list.Len())
+	for i := range values {
+		values[i] = list.Index(i)
+	}
+	return chunkValues(values, size)
+}
+
+// chunkValues splits a huge set of values into many smaller sets
+func chunkValues(list []reflect.Value, size int) [][]reflect.Value {
 	rs := make([][]reflect.Value, 0)
-	totalItem := list.Len()
-	remaining := totalItem
-	lastIndex := 0
-	for remaining > 0 {
-		thisBatchSize := remaining
-		if remaining > size {
+	if size <= 0 {
+		size = len(list)
+	}
+	for len(list) > 0 {
+		thisBatchSize := len(list)
+		if thisBatchSize > size {
 			thisBatchSize = size
 		}
-		remaining = remaining - thisBatchSize
-		batch := make([]reflect.Value, thisBatchSize)
-		for i := 0; i < thisBatchSize; i++ {
-			batch[i] = list.Index(i + lastIndex)
-		}
-		lastIndex += thisBatchSize
-		rs = append(rs, batch)
+		rs = append(rs, list[:thisBatchSize])
+		list = list[thisBatchSize:]
 	}
 	return rs
 }
@@ -0,0 +1,300 @@
+package xsql
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mappingInner struct {
+	X string `column:"x"`
+	Y string `column:"y"`
+}
+
+type mappingOther struct {
+	X string `column:"x"`
+}
+
+type mappingShadowFirst struct {
+	X     string       `column:"x"`
+	Inner mappingInner `column:"__embedded"`
+}
+
+type mappingShadowLast struct {
+	A mappingInner `column:"__embedded"`
+	B mappingOther `column:"__embedded"`
+	X string       `column:"x"`
+}
+
+type mappingConflict struct {
+	A mappingInner `column:"__embedded"`
+	B mappingOther `column:"__embedded"`
+}
+
+type mappingComposite struct {
+	UserId int64 `column:"user_id,pk"`
+	RoleId int64 `column:"role_id,pk"`
+	Name   string
+}
+
+type mappingUntaggedID struct {
+	ID   int64
+	Name string
+}
+
+type mappingOptions struct {
+	BaseModel `column:"__embedded"`
+	Secret    string     `column:"-"`
+	Code      string     `column:"code, omitempty ,noupdate"`
+	DeletedAt *time.Time `column:"deleted_at,softdelete"`
+	Version   int64      `column:"version,version"`
+}
+
+type MappingPart struct {
+	X string `column:"x"`
+	Y string `column:"y"`
+}
+
+type mappingPointer struct {
+	*MappingPart `column:"__embedded"`
+	Z            string `column:"z"`
+}
+
+type mappingUnexportedPointer struct {
+	*mappingInner `column:"__embedded"`
+	Z             string `column:"z"`
+}
+
+type mappingUnexportedField struct {
+	inner mappingInner `column:"__embedded"`
+}
+
+type mappingNullSoftDelete struct {
+	Id  int64        `column:"id"`
+	Del sql.NullTime `column:"del,softdelete"`
+}
+
+type mappingStringTimestamp struct {
+	Id      int64  `column:"id"`
+	Updated string `column:"updated,autoUpdateTime"`
+}
+
+type mappingUnexportedValue struct {
+	mappingInner `column:"__embedded"`
+}
+
+func TestGetMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     reflect.Type
+		columns []string
+		paths   []string
+		pk      []string
+		err     string
+	}{
+		{
+			name:    "base model",
+			typ:     reflect.TypeOf(BaseModel{}),
+			columns: []string{"id", "created", "updated"},
+			paths:   []string{"Id", "Created", "Updated"},
+			pk:      []string{"id"},
+		},
+		{
+			name:    "composite key",
+			typ:     reflect.TypeOf(mappingComposite{}),
+			columns: []string{"user_id", "role_id", "Name"},
+			paths:   []string{"UserId", "RoleId", "Name"},
+			pk:      []string{"user_id", "role_id"},
+		},
+		{
+			name:    "pointer of struct",
+			typ:     reflect.TypeOf(&mappingComposite{}),
+			columns: []string{"user_id", "role_id", "Name"},
+			paths:   []string{"UserId", "RoleId", "Name"},
+			pk:      []string{"user_id", "role_id"},
+		},
+		{
+			name:    "untagged id",
+			typ:     reflect.TypeOf(mappingUntaggedID{}),
+			columns: []string{"ID", "Name"},
+			paths:   []string{"ID", "Name"},
+			pk:      []string{"ID"},
+		},
+		{
+			name:    "options",
+			typ:     reflect.TypeOf(mappingOptions{}),
+			columns: []string{"id", "created", "updated", "code", "deleted_at", "version"},
+			paths:   []string{"BaseModel.Id", "BaseModel.Created", "BaseModel.Updated", "Code", "DeletedAt", "Version"},
+			pk:      []string{"id"},
+		},
+		{
+			name:    "outer field declared first shadows embedded field",
+			typ:     reflect.TypeOf(mappingShadowFirst{}),
+			columns: []string{"x", "y"},
+			paths:   []string{"X", "Inner.Y"},
+		},
+		{
+			name:    "outer field declared last shadows embedded fields",
+			typ:     reflect.TypeOf(mappingShadowLast{}),
+			columns: []string{"y", "x"},
+			paths:   []string{"A.Y", "X"},
+		},
+		{
+			name: "fields at the same depth",
+			typ:  reflect.TypeOf(mappingConflict{}),
+			err:  "column x is mapped to both fields A.X and B.X",
+		},
+		{
+			name:    "embedded pointer",
+			typ:     reflect.TypeOf(mappingPointer{}),
+			columns: []string{"x", "y", "z"},
+			paths:   []string{"MappingPart.X", "MappingPart.Y", "Z"},
+		},
+		{
+			name:    "unexported embedded value",
+			typ:     reflect.TypeOf(mappingUnexportedValue{}),
+			columns: []string{"x", "y"},
+			paths:   []string{"mappingInner.X", "mappingInner.Y"},
+		},
+		{
+			name: "soft-delete column of unsupported type",
+			typ:  reflect.TypeOf(mappingNullSoftDelete{}),
+			err:  "column del of xsql.mappingNullSoftDelete is declared as softdelete",
+		},
+		{
+			name: "timestamp column of unsupported type",
+			typ:  reflect.TypeOf(mappingStringTimestamp{}),
+			err:  "column updated of xsql.mappingStringTimestamp is declared as autoUpdateTime",
+		},
+		{
+			name: "unexported embedded pointer",
+			typ:  reflect.TypeOf(mappingUnexportedPointer{}),
+			err:  "embedded field mappingInner of xsql.mappingUnexportedPointer must be exported",
+		},
+		{
+			name: "unexported embedded field",
+			typ:  reflect.TypeOf(mappingUnexportedField{}),
+			err:  "embedded field inner of xsql.mappingUnexportedField must be exported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := getMapping(tt.typ)
+			if tt.err != "" {
+				if tm.err == nil || !strings.Contains(tm.err.Error(), tt.err) {
+					t.Fatalf("expected error %q, got %v", tt.err, tm.err)
+				}
+				return
+			}
+			if tm.err != nil {
+				t.Fatalf("unexpected error: %v", tm.err)
+			}
+			var columns, paths, pk []string
+			for _, f := range tm.fields {
+				columns = append(columns, f.column)
+				paths = append(paths, tm.fieldPath(f.index))
+				if tm.byColumn[f.column] != f {
+					t.Errorf("column %s is not indexed", f.column)
+				}
+			}
+			for _, f := range tm.pk {
+				pk = append(pk, f.column)
+			}
+			if !reflect.DeepEqual(columns, tt.columns) {
+				t.Errorf("columns: expected %v, got %v", tt.columns, columns)
+			}
+			if !reflect.DeepEqual(paths, tt.paths) {
+				t.Errorf("fields: expected %v, got %v", tt.paths, paths)
+			}
+			if !reflect.DeepEqual(pk, tt.pk) {
+				t.Errorf("primary key: expected %v, got %v", tt.pk, pk)
+			}
+		})
+	}
+}
+
+func TestGetMappingOptions(t *testing.T) {
+	tm := getMapping(reflect.TypeOf(mappingOptions{}))
+	if tm.err != nil {
+		t.Fatalf("unexpected error: %v", tm.err)
+	}
+	code := tm.byColumn["code"]
+	if !code.options.omitEmpty() || code.options.updatable() || !code.options.insertable() {
+		t.Errorf("unexpected options of code: %v", code.options)
+	}
+	if tm.softDelete == nil || tm.softDelete.column != "deleted_at" {
+		t.Fatalf("expected soft-delete column deleted_at, got %v", tm.softDelete)
+	}
+	if tm.softDelete.options.updatable() {
+		t.Errorf("soft-delete column must not be updatable")
+	}
+	if tm.version == nil || tm.version.column != "version" {
+		t.Errorf("expected version column version, got %v", tm.version)
+	}
+	if !tm.byColumn["created"].options.has("autocreatetime") {
+		t.Errorf("options must be matched regardless of case")
+	}
+}
+
+func TestFieldMappingValue(t *testing.T) {
+	tm := getMapping(reflect.TypeOf(mappingPointer{}))
+	x := tm.byColumn["x"]
+
+	var m mappingPointer
+	if v := x.value(reflect.ValueOf(m)); v.String() != "" {
+		t.Errorf("expected zero value of unsettable nil pointer, got %v", v)
+	}
+	if m.MappingPart != nil {
+		t.Fatalf("unsettable value must not be allocated")
+	}
+	x.value(reflect.ValueOf(&m).Elem()).SetString("value")
+	if m.MappingPart == nil || m.X != "value" {
+		t.Errorf("expected nil embedded pointer to be allocated, got %+v", m.MappingPart)
+	}
+
+	// nil unexported embedded pointer can not be allocated
+	var u mappingUnexportedPointer
+	rm := ResultMapper{Type: reflect.TypeOf(u)}
+	if _, err := rm.scanArgs(reflect.ValueOf(&u).Elem(), []string{"x", "z"}); err == nil {
+		t.Errorf("expected error of unexported embedded pointer")
+	}
+}
+
+func TestTagOptions(t *testing.T) {
+	tests := []struct {
+		tag        string
+		column     string
+		insertable bool
+		updatable  bool
+		omitEmpty  bool
+	}{
+		{"name", "name", true, true, false},
+		{"name,omitempty", "name", true, true, true},
+		{"name,default", "name", true, true, true},
+		{"id,pk,autoincrement", "id", true, true, true},
+		{"total,readonly", "total", false, false, false},
+		{"code,noinsert", "code", false, true, false},
+		{"created_by,noupdate", "created_by", true, false, false},
+		{"deleted_at,softdelete", "deleted_at", true, false, true},
+		{"name, NoUpdate ,", "name", true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			column, opts := parseTag(tt.tag)
+			if column != tt.column {
+				t.Errorf("column: expected %s, got %s", tt.column, column)
+			}
+			if opts.insertable() != tt.insertable {
+				t.Errorf("insertable: expected %v, got %v", tt.insertable, opts.insertable())
+			}
+			if opts.updatable() != tt.updatable {
+				t.Errorf("updatable: expected %v, got %v", tt.updatable, opts.updatable())
+			}
+			if opts.omitEmpty() != tt.omitEmpty {
+				t.Errorf("omitEmpty: expected %v, got %v", tt.omitEmpty, opts.omitEmpty())
+			}
+		})
+	}
+}
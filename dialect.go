@@ -1,6 +1,9 @@
 package xsql
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type SQLiteDialect struct {
 }
@@ -13,10 +16,60 @@ func (SQLiteDialect) Parameterizie(numberOfValue int) []string {
 	return rs
 }
 
+func (SQLiteDialect) Savepoint(name string) string {
+	return fmt.Sprintf(`SAVEPOINT %s`, name)
+}
+
+func (SQLiteDialect) ReleaseSavepoint(name string) string {
+	return fmt.Sprintf(`RELEASE SAVEPOINT %s`, name)
+}
+
+func (SQLiteDialect) RollbackToSavepoint(name string) string {
+	return fmt.Sprintf(`ROLLBACK TO SAVEPOINT %s`, name)
+}
+
+func (SQLiteDialect) Paginate(limit, offset int64) string {
+	return fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
+}
+
+func (SQLiteDialect) KeyReturning() KeyReturning {
+	return ReturningClause
+}
+
+// NextSequenceValue returns empty string since SQLite does not support sequence
+func (SQLiteDialect) NextSequenceValue(name string) string {
+	return ""
+}
+
+func (d SQLiteDialect) Upsert(table string, columns, conflictColumns, updateColumns []string, rows int) string {
+	return insertOnConflict(d, table, columns, conflictColumns, updateColumns, rows)
+}
+
 type MySQLDialect struct {
 	SQLiteDialect
 }
 
+func (MySQLDialect) KeyReturning() KeyReturning {
+	return ReturningLastInsertId
+}
+
+func (d MySQLDialect) Upsert(table string, columns, conflictColumns, updateColumns []string, rows int) string {
+	sets := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		sets[i] = fmt.Sprintf(`%s = VALUES(%s)`, column, column)
+	}
+	if len(sets) == 0 {
+		// do nothing with existing row
+		sets = append(sets, fmt.Sprintf(`%s = %s`, conflictColumns[0], conflictColumns[0]))
+	}
+	return fmt.Sprintf(`INSERT INTO %s(%s) VALUES %s ON DUPLICATE KEY UPDATE %s`,
+		table,
+		strings.Join(columns, ","),
+		valuesPlaceHolder(d, len(columns), rows),
+		strings.Join(sets, ", "),
+	)
+}
+
 type PostgreDialect struct {
 }
 
@@ -28,6 +81,34 @@ func (PostgreDialect) Parameterizie(numberOfValue int) []string {
 	return rs
 }
 
+func (PostgreDialect) Savepoint(name string) string {
+	return fmt.Sprintf(`SAVEPOINT %s`, name)
+}
+
+func (PostgreDialect) ReleaseSavepoint(name string) string {
+	return fmt.Sprintf(`RELEASE SAVEPOINT %s`, name)
+}
+
+func (PostgreDialect) RollbackToSavepoint(name string) string {
+	return fmt.Sprintf(`ROLLBACK TO SAVEPOINT %s`, name)
+}
+
+func (PostgreDialect) Paginate(limit, offset int64) string {
+	return fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
+}
+
+func (PostgreDialect) KeyReturning() KeyReturning {
+	return ReturningClause
+}
+
+func (PostgreDialect) NextSequenceValue(name string) string {
+	return fmt.Sprintf(`SELECT nextval('%s')`, name)
+}
+
+func (d PostgreDialect) Upsert(table string, columns, conflictColumns, updateColumns []string, rows int) string {
+	return insertOnConflict(d, table, columns, conflictColumns, updateColumns, rows)
+}
+
 type OracleDialect struct {
 }
 
@@ -39,6 +120,149 @@ func (OracleDialect) Parameterizie(numberOfValue int) []string {
 	return rs
 }
 
+func (OracleDialect) Savepoint(name string) string {
+	return fmt.Sprintf(`SAVEPOINT %s`, name)
+}
+
+// ReleaseSavepoint returns empty string since Oracle does not support releasing savepoint
+func (OracleDialect) ReleaseSavepoint(name string) string {
+	return ""
+}
+
+func (OracleDialect) RollbackToSavepoint(name string) string {
+	return fmt.Sprintf(`ROLLBACK TO SAVEPOINT %s`, name)
+}
+
+func (OracleDialect) Paginate(limit, offset int64) string {
+	return fmt.Sprintf(`OFFSET %d ROWS FETCH NEXT %d ROWS ONLY`, offset, limit)
+}
+
+func (OracleDialect) KeyReturning() KeyReturning {
+	return ReturningInto
+}
+
+func (OracleDialect) NextSequenceValue(name string) string {
+	return fmt.Sprintf(`SELECT %s.NEXTVAL FROM dual`, name)
+}
+
+func (d OracleDialect) Upsert(table string, columns, conflictColumns, updateColumns []string, rows int) string {
+	params := d.Parameterizie(len(columns) * rows)
+	selects := make([]string, rows)
+	for i := 0; i < rows; i++ {
+		fields := make([]string, len(columns))
+		for j, column := range columns {
+			fields[j] = fmt.Sprintf(`%s %s`, params[i*len(columns)+j], column)
+		}
+		selects[i] = fmt.Sprintf(`SELECT %s FROM dual`, strings.Join(fields, ", "))
+	}
+	conds := make([]string, len(conflictColumns))
+	for i, column := range conflictColumns {
+		conds[i] = fmt.Sprintf(`t.%s = s.%s`, column, column)
+	}
+	sets := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		sets[i] = fmt.Sprintf(`t.%s = s.%s`, column, column)
+	}
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = fmt.Sprintf(`s.%s`, column)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`MERGE INTO %s t USING (%s) s ON (%s)`,
+		table, strings.Join(selects, " UNION ALL "), strings.Join(conds, " AND ")))
+	if len(sets) > 0 {
+		b.WriteString(fmt.Sprintf(` WHEN MATCHED THEN UPDATE SET %s`, strings.Join(sets, ", ")))
+	}
+	b.WriteString(fmt.Sprintf(` WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)`,
+		strings.Join(columns, ","), strings.Join(values, ",")))
+	return b.String()
+}
+
+// savepoint returns statement which creates a savepoint by given dialect
+func savepoint(d Dialect, name string) string {
+	if sd, ok := d.(SavepointDialect); ok {
+		return sd.Savepoint(name)
+	}
+	return fmt.Sprintf(`SAVEPOINT %s`, name)
+}
+
+// releaseSavepoint returns statement which releases a savepoint by given dialect
+func releaseSavepoint(d Dialect, name string) string {
+	if sd, ok := d.(SavepointDialect); ok {
+		return sd.ReleaseSavepoint(name)
+	}
+	return fmt.Sprintf(`RELEASE SAVEPOINT %s`, name)
+}
+
+// rollbackToSavepoint returns statement which rolls back to a savepoint by given dialect
+func rollbackToSavepoint(d Dialect, name string) string {
+	if sd, ok := d.(SavepointDialect); ok {
+		return sd.RollbackToSavepoint(name)
+	}
+	return fmt.Sprintf(`ROLLBACK TO SAVEPOINT %s`, name)
+}
+
+// paginate returns clause which limits result of a query by given dialect
+func paginate(d Dialect, limit, offset int64) string {
+	if pd, ok := d.(PaginationDialect); ok {
+		return pd.Paginate(limit, offset)
+	}
+	return fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
+}
+
+// keyReturning returns the way of reading generated keys by given dialect
+func keyReturning(d Dialect) KeyReturning {
+	if kd, ok := d.(KeyReturningDialect); ok {
+		return kd.KeyReturning()
+	}
+	return ReturningNone
+}
+
+// nextSequenceValue returns statement which selects next value of a sequence by given dialect
+func nextSequenceValue(d Dialect, name string) string {
+	if sd, ok := d.(SequenceDialect); ok {
+		return sd.NextSequenceValue(name)
+	}
+	return ""
+}
+
+// upsert returns upsert statement by given dialect
+func upsert(d Dialect, table string, columns, conflictColumns, updateColumns []string, rows int) string {
+	if ud, ok := d.(UpsertDialect); ok {
+		return ud.Upsert(table, columns, conflictColumns, updateColumns, rows)
+	}
+	return insertOnConflict(d, table, columns, conflictColumns, updateColumns, rows)
+}
+
+// valuesPlaceHolder returns parameter place holders of given number of rows,
+// e.g. ($1,$2),($3,$4)
+func valuesPlaceHolder(d Dialect, numberOfColumns, rows int) string {
+	paramPlaceHolder := strRepeat("(", ")", "%s", ",", numberOfColumns)
+	sqlParams := strRepeat("", "", paramPlaceHolder, ",", rows)
+	return fmt.Sprintf(sqlParams, strToIntf(d.Parameterizie(numberOfColumns*rows))...)
+}
+
+// insertOnConflict returns an INSERT ... ON CONFLICT statement which is
+// supported by both PostgreSQL and SQLite
+func insertOnConflict(d Dialect, table string, columns, conflictColumns, updateColumns []string, rows int) string {
+	action := `DO NOTHING`
+	if len(updateColumns) > 0 {
+		sets := make([]string, len(updateColumns))
+		for i, column := range updateColumns {
+			sets[i] = fmt.Sprintf(`%s = EXCLUDED.%s`, column, column)
+		}
+		action = fmt.Sprintf(`DO UPDATE SET %s`, strings.Join(sets, ", "))
+	}
+	return fmt.Sprintf(`INSERT INTO %s(%s) VALUES %s ON CONFLICT (%s) %s`,
+		table,
+		strings.Join(columns, ","),
+		valuesPlaceHolder(d, len(columns), rows),
+		strings.Join(conflictColumns, ","),
+		action,
+	)
+}
+
 func getDbDialect(driver string) (Dialect, error) {
 	switch driver {
 	case "postgresql", "postgres", "pg", "psql":
@@ -47,7 +271,7 @@ func getDbDialect(driver string) (Dialect, error) {
 		return MySQLDialect{}, nil
 	case "sqlite", "sqlite3":
 		return SQLiteDialect{}, nil
-	case "ora", "oracle", "gordor":
+	case "ora", "oracle", "godror", "gordor":
 		return OracleDialect{}, nil
 	}
 	return nil, fmt.Errorf(`no such dialect of %s`, driver)
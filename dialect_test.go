@@ -0,0 +1,130 @@
+package xsql
+
+import "testing"
+
+// customDialect implements only the required methods of Dialect
+type customDialect struct {
+}
+
+func (customDialect) Parameterizie(numberOfValue int) []string {
+	return SQLiteDialect{}.Parameterizie(numberOfValue)
+}
+
+func TestUpsert(t *testing.T) {
+	columns := []string{"id", "name", "price"}
+	conflictColumns := []string{"id"}
+	updateColumns := []string{"name", "price"}
+	tests := []struct {
+		name          string
+		dialect       Dialect
+		updateColumns []string
+		rows          int
+		expected      string
+	}{
+		{
+			name:          "sqlite",
+			dialect:       SQLiteDialect{},
+			updateColumns: updateColumns,
+			rows:          2,
+			expected:      `INSERT INTO item(id,name,price) VALUES (?,?,?),(?,?,?) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, price = EXCLUDED.price`,
+		},
+		{
+			name:     "sqlite without update columns",
+			dialect:  SQLiteDialect{},
+			rows:     1,
+			expected: `INSERT INTO item(id,name,price) VALUES (?,?,?) ON CONFLICT (id) DO NOTHING`,
+		},
+		{
+			name:          "postgres",
+			dialect:       PostgreDialect{},
+			updateColumns: updateColumns,
+			rows:          2,
+			expected:      `INSERT INTO item(id,name,price) VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, price = EXCLUDED.price`,
+		},
+		{
+			name:          "mysql",
+			dialect:       MySQLDialect{},
+			updateColumns: updateColumns,
+			rows:          2,
+			expected:      `INSERT INTO item(id,name,price) VALUES (?,?,?),(?,?,?) ON DUPLICATE KEY UPDATE name = VALUES(name), price = VALUES(price)`,
+		},
+		{
+			name:     "mysql without update columns",
+			dialect:  MySQLDialect{},
+			rows:     1,
+			expected: `INSERT INTO item(id,name,price) VALUES (?,?,?) ON DUPLICATE KEY UPDATE id = id`,
+		},
+		{
+			name:          "oracle",
+			dialect:       OracleDialect{},
+			updateColumns: updateColumns,
+			rows:          2,
+			expected: `MERGE INTO item t USING (SELECT :1 id, :2 name, :3 price FROM dual UNION ALL SELECT :4 id, :5 name, :6 price FROM dual) s ON (t.id = s.id)` +
+				` WHEN MATCHED THEN UPDATE SET t.name = s.name, t.price = s.price` +
+				` WHEN NOT MATCHED THEN INSERT (id,name,price) VALUES (s.id,s.name,s.price)`,
+		},
+		{
+			name:     "oracle without update columns",
+			dialect:  OracleDialect{},
+			rows:     1,
+			expected: `MERGE INTO item t USING (SELECT :1 id, :2 name, :3 price FROM dual) s ON (t.id = s.id) WHEN NOT MATCHED THEN INSERT (id,name,price) VALUES (s.id,s.name,s.price)`,
+		},
+		{
+			name:          "custom dialect",
+			dialect:       customDialect{},
+			updateColumns: updateColumns,
+			rows:          1,
+			expected:      `INSERT INTO item(id,name,price) VALUES (?,?,?) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, price = EXCLUDED.price`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := upsert(tt.dialect, "item", columns, conflictColumns, tt.updateColumns, tt.rows)
+			if actual != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		expected string
+	}{
+		{"sqlite", SQLiteDialect{}, `LIMIT 20 OFFSET 40`},
+		{"mysql", MySQLDialect{}, `LIMIT 20 OFFSET 40`},
+		{"postgres", PostgreDialect{}, `LIMIT 20 OFFSET 40`},
+		{"oracle", OracleDialect{}, `OFFSET 40 ROWS FETCH NEXT 20 ROWS ONLY`},
+		{"custom dialect", customDialect{}, `LIMIT 20 OFFSET 40`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := paginate(tt.dialect, 20, 40); actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestKeyReturning(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		expected KeyReturning
+	}{
+		{"sqlite", SQLiteDialect{}, ReturningClause},
+		{"mysql", MySQLDialect{}, ReturningLastInsertId},
+		{"postgres", PostgreDialect{}, ReturningClause},
+		{"oracle", OracleDialect{}, ReturningInto},
+		{"custom dialect", customDialect{}, ReturningNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := keyReturning(tt.dialect); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/locngoxuan/xsql"
@@ -35,9 +34,7 @@ func main() {
 		s := "Item with id = 1"
 		example := ExampleTable{
 			BaseModel: xsql.BaseModel{
-				Id:      1,
-				Created: time.Now(),
-				Updated: time.Now(),
+				Id: 1,
 			},
 			Text: &s,
 		}
@@ -54,9 +51,7 @@ func main() {
 			s := fmt.Sprintf("Item with id = %d", i+2)
 			examples = append(examples, ExampleTable{
 				BaseModel: xsql.BaseModel{
-					Id:      int64(i + 2),
-					Created: time.Now(),
-					Updated: time.Now(),
+					Id: int64(i + 2),
 				},
 				Text: &s,
 			})
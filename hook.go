@@ -0,0 +1,130 @@
+package xsql
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+)
+
+// txHooks holds callbacks which are fired when a transaction finishes
+type txHooks struct {
+	mu         sync.Mutex
+	onCommit   []func()
+	onRollback []func()
+}
+
+// hookMark is the number of registered callbacks at a specific moment
+type hookMark struct {
+	commit   int
+	rollback int
+}
+
+// OnCommit registers fn which is called after given transaction of the default
+// client is committed. See Client.OnCommit
+func OnCommit(tx *sql.Tx, fn func()) error {
+	return std.OnCommit(tx, fn)
+}
+
+// OnRollback registers fn which is called after given transaction of the default
+// client is rolled back. See Client.OnRollback
+func OnRollback(tx *sql.Tx, fn func()) error {
+	return std.OnRollback(tx, fn)
+}
+
+// OnCommit registers fn which is called after given transaction is committed.
+// The transaction must be started by xsql, e.g. by WithTx, InTx or any function
+// which does not take a transaction. Otherwise, ErrTxNotManaged is returned.
+// Callbacks are called in order of registration.
+func (c *Client) OnCommit(tx *sql.Tx, fn func()) error {
+	h, ok := c.txHooks(tx)
+	if !ok {
+		return ErrTxNotManaged
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onCommit = append(h.onCommit, fn)
+	return nil
+}
+
+// OnRollback registers fn which is called after given transaction is rolled back.
+// If fn is registered within WithSavepoint, it is also called when the transaction
+// is rolled back to that savepoint. Callbacks are called in reverse order of
+// registration, so that later changes are undone first.
+// The transaction must be started by xsql, otherwise ErrTxNotManaged is returned.
+func (c *Client) OnRollback(tx *sql.Tx, fn func()) error {
+	h, ok := c.txHooks(tx)
+	if !ok {
+		return ErrTxNotManaged
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onRollback = append(h.onRollback, fn)
+	return nil
+}
+
+// txHooks returns callbacks of given transaction if it is managed by client
+func (c *Client) txHooks(tx *sql.Tx) (*txHooks, bool) {
+	v, ok := c.hooks.Load(tx)
+	if !ok {
+		return nil, false
+	}
+	return v.(*txHooks), true
+}
+
+// mark returns current number of registered callbacks
+func (h *txHooks) mark() hookMark {
+	if h == nil {
+		return hookMark{}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return hookMark{
+		commit:   len(h.onCommit),
+		rollback: len(h.onRollback),
+	}
+}
+
+// rollbackTo drops callbacks registered after given mark then fires
+// the dropped rollback callbacks
+func (h *txHooks) rollbackTo(m hookMark) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	fns := append([]func(){}, h.onRollback[m.rollback:]...)
+	h.onCommit = h.onCommit[:m.commit]
+	h.onRollback = h.onRollback[:m.rollback]
+	h.mu.Unlock()
+	for i := len(fns) - 1; i >= 0; i-- {
+		fns[i]()
+	}
+}
+
+func (h *txHooks) fireCommit() {
+	h.mu.Lock()
+	fns := h.onCommit
+	h.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+func (h *txHooks) fireRollback() {
+	h.mu.Lock()
+	fns := h.onRollback
+	h.mu.Unlock()
+	for i := len(fns) - 1; i >= 0; i-- {
+		fns[i]()
+	}
+}
+
+// restoreOnRollback keeps current value of field, which is going to be changed within tx,
+// and restores it when tx is rolled back. Therefore, a transaction which is re-run by retry
+// policy starts from the original model. It does nothing if tx is not managed by client
+func (c *Client) restoreOnRollback(tx *sql.Tx, field reflect.Value) {
+	old := reflect.New(field.Type()).Elem()
+	old.Set(field)
+	_ = c.OnRollback(tx, func() {
+		field.Set(old)
+	})
+}
@@ -0,0 +1,108 @@
+package xsql
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recorder records names of fired callbacks
+type recorder []string
+
+func (r *recorder) add(name string) func() {
+	return func() {
+		*r = append(*r, name)
+	}
+}
+
+func TestTxHooksFireCommit(t *testing.T) {
+	var fired recorder
+	h := &txHooks{}
+	h.onCommit = append(h.onCommit, fired.add("c1"), fired.add("c2"))
+	h.onRollback = append(h.onRollback, fired.add("r1"))
+	h.fireCommit()
+	if expected := (recorder{"c1", "c2"}); !reflect.DeepEqual(fired, expected) {
+		t.Errorf("expected %v, got %v", expected, fired)
+	}
+}
+
+func TestTxHooksFireRollback(t *testing.T) {
+	var fired recorder
+	h := &txHooks{}
+	h.onCommit = append(h.onCommit, fired.add("c1"))
+	h.onRollback = append(h.onRollback, fired.add("r1"), fired.add("r2"), fired.add("r3"))
+	h.fireRollback()
+	// later changes are undone first
+	if expected := (recorder{"r3", "r2", "r1"}); !reflect.DeepEqual(fired, expected) {
+		t.Errorf("expected %v, got %v", expected, fired)
+	}
+}
+
+func TestTxHooksRollbackTo(t *testing.T) {
+	var fired recorder
+	h := &txHooks{}
+	h.onCommit = append(h.onCommit, fired.add("c1"))
+	h.onRollback = append(h.onRollback, fired.add("r1"))
+
+	// savepoint
+	mark := h.mark()
+	if expected := (hookMark{commit: 1, rollback: 1}); mark != expected {
+		t.Fatalf("expected mark %v, got %v", expected, mark)
+	}
+	h.onCommit = append(h.onCommit, fired.add("c2"))
+	h.onRollback = append(h.onRollback, fired.add("r2"), fired.add("r3"))
+
+	// rolled back to savepoint, callbacks registered after savepoint are fired and dropped
+	h.rollbackTo(mark)
+	if expected := (recorder{"r3", "r2"}); !reflect.DeepEqual(fired, expected) {
+		t.Errorf("expected %v, got %v", expected, fired)
+	}
+	if m := h.mark(); m != mark {
+		t.Errorf("expected callbacks truncated to %v, got %v", mark, m)
+	}
+
+	// commit callbacks of the rolled back savepoint are not fired
+	fired = nil
+	h.fireCommit()
+	if expected := (recorder{"c1"}); !reflect.DeepEqual(fired, expected) {
+		t.Errorf("expected %v, got %v", expected, fired)
+	}
+
+	// rollback callbacks of the rolled back savepoint are not fired again
+	fired = nil
+	h.fireRollback()
+	if expected := (recorder{"r1"}); !reflect.DeepEqual(fired, expected) {
+		t.Errorf("expected %v, got %v", expected, fired)
+	}
+}
+
+func TestTxHooksNestedSavepoints(t *testing.T) {
+	var fired recorder
+	h := &txHooks{}
+	outer := h.mark()
+	h.onRollback = append(h.onRollback, fired.add("r1"))
+	inner := h.mark()
+	h.onRollback = append(h.onRollback, fired.add("r2"))
+	h.onCommit = append(h.onCommit, fired.add("c2"))
+
+	h.rollbackTo(inner)
+	if expected := (recorder{"r2"}); !reflect.DeepEqual(fired, expected) {
+		t.Errorf("expected %v, got %v", expected, fired)
+	}
+	fired = nil
+	h.rollbackTo(outer)
+	if expected := (recorder{"r1"}); !reflect.DeepEqual(fired, expected) {
+		t.Errorf("expected %v, got %v", expected, fired)
+	}
+	if m := h.mark(); m != outer {
+		t.Errorf("expected callbacks truncated to %v, got %v", outer, m)
+	}
+}
+
+func TestTxHooksNil(t *testing.T) {
+	// transactions which are not managed by xsql do not have hooks
+	var h *txHooks
+	if m := h.mark(); m != (hookMark{}) {
+		t.Errorf("expected empty mark, got %v", m)
+	}
+	h.rollbackTo(hookMark{})
+}
@@ -0,0 +1,215 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// IDGenerator generates ids of models which are inserted with zero primary key.
+// It is configured by DbOption.IDGenerator
+type IDGenerator interface {
+	// NextID returns a new id for a record of given table. The record is going to be
+	// inserted within tx
+	NextID(ctx context.Context, c *Client, tx *sql.Tx, table string) (int64, error)
+}
+
+// assignID sets a new id into primary key of given struct value if client has an IDGenerator,
+// and the primary key is a single integer field whose value is zero
+func (c *Client) assignID(ctx context.Context, tx *sql.Tx, val reflect.Value, table string) error {
+	if c.idGenerator == nil {
+		return nil
+	}
+	_, pkFields, err := getPrimaryKeys(val.Type())
+	if err != nil || len(pkFields) != 1 {
+		return nil
+	}
+	field := pkFields[0].value(val)
+	if !field.IsZero() {
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil
+	}
+	id, err := c.idGenerator.NextID(ctx, c, tx, table)
+	if err != nil {
+		return err
+	}
+	// truncated ids may collide with others, e.g. ids of Snowflake do not fit into 32 bits
+	if overflowInt(field, id) {
+		return fmt.Errorf(`id %d generated for %s overflows primary key of type %s`, id, table, field.Type())
+	}
+	setInt(field, id)
+	return nil
+}
+
+// Snowflake generates 64-bit ids which are composed of
+// 41 bits of milliseconds since Epoch, 10 bits of node and 12 bits of sequence.
+// Ids are unique across nodes having different node numbers without database round trip
+type Snowflake struct {
+	mu       sync.Mutex
+	epoch    time.Time
+	node     int64
+	lastTime int64
+	sequence int64
+}
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNode      = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+)
+
+// SnowflakeEpoch is the default epoch of Snowflake
+var SnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewSnowflake creates a Snowflake generator of given node which is in range [0, 1023]
+func NewSnowflake(node int64) (*Snowflake, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, fmt.Errorf(`node must be in range [0, %d]`, snowflakeMaxNode)
+	}
+	return &Snowflake{
+		epoch: SnowflakeEpoch,
+		node:  node,
+	}, nil
+}
+
+func (s *Snowflake) NextID(ctx context.Context, c *Client, tx *sql.Tx, table string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Since(s.epoch).Milliseconds()
+	if now < s.lastTime {
+		// clock moved backwards, keep using the last time
+		now = s.lastTime
+	}
+	if now == s.lastTime {
+		s.sequence = (s.sequence + 1) & snowflakeMaxSequence
+		if s.sequence == 0 {
+			// sequence is exhausted within this millisecond, wait for the next one
+			for now <= s.lastTime {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Since(s.epoch).Milliseconds()
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastTime = now
+	return now<<(snowflakeNodeBits+snowflakeSequenceBits) | s.node<<snowflakeSequenceBits | s.sequence, nil
+}
+
+// HiLo generates ids by blocks. A block of BlockSize ids is reserved by increasing
+// the `hi` value of table in Table, then ids are given from memory until the block
+// is exhausted. Table must be created before using, e.g.
+//
+//	CREATE TABLE xsql_hilo (name VARCHAR(255) PRIMARY KEY, next_hi BIGINT NOT NULL)
+//
+// Blocks are reserved within separated transactions, so that they are never reused
+// even if the transaction of insert is rolled back. Since such a transaction needs
+// another connection while the connection of insert is held, DbOption.MaxOpenConns
+// must be either 0 or at least 2, otherwise reserving a block blocks forever.
+type HiLo struct {
+	mu        sync.Mutex
+	table     string
+	blockSize int64
+	blocks    map[string]*hiloBlock
+}
+
+type hiloBlock struct {
+	next int64
+	max  int64
+}
+
+// NewHiLo creates a HiLo generator which stores `hi` values in given table
+func NewHiLo(table string, blockSize int64) (*HiLo, error) {
+	if blockSize < 1 {
+		return nil, fmt.Errorf(`block size must be greater than 0`)
+	}
+	return &HiLo{
+		table:     table,
+		blockSize: blockSize,
+		blocks:    make(map[string]*hiloBlock),
+	}, nil
+}
+
+func (h *HiLo) NextID(ctx context.Context, c *Client, tx *sql.Tx, table string) (int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b, ok := h.blocks[table]
+	if !ok || b.next > b.max {
+		hi, err := h.reserve(ctx, c, table)
+		if err != nil {
+			return 0, err
+		}
+		b = &hiloBlock{
+			next: hi*h.blockSize + 1,
+			max:  (hi + 1) * h.blockSize,
+		}
+		h.blocks[table] = b
+	}
+	id := b.next
+	b.next++
+	return id, nil
+}
+
+// reserve increases `hi` value of given table and returns its previous value
+func (h *HiLo) reserve(ctx context.Context, c *Client, table string) (hi int64, err error) {
+	err = c.runTxOnce(ctx, &sql.TxOptions{}, func(tx *sql.Tx) error {
+		params := map[string]interface{}{
+			"name": table,
+		}
+		stmt := NewStmt(fmt.Sprintf(`UPDATE %s SET next_hi = next_hi + 1 WHERE name = :name`, h.table)).
+			With(params)
+		i, err := execTxContext(ctx, tx, stmt.build(c.dialect), stmt.GetParams()...)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			stmt = NewStmt(fmt.Sprintf(`INSERT INTO %s(name, next_hi) VALUES (:name, 1)`, h.table)).
+				With(params)
+			_, err = execTxContext(ctx, tx, stmt.build(c.dialect), stmt.GetParams()...)
+			hi = 0
+			return err
+		}
+		stmt = NewStmt(fmt.Sprintf(`SELECT next_hi FROM %s WHERE name = :name`, h.table)).
+			With(params)
+		err = tx.QueryRowContext(ctx, stmt.build(c.dialect), stmt.GetParams()...).Scan(&hi)
+		if err != nil {
+			return err
+		}
+		hi--
+		return nil
+	})
+	return
+}
+
+// Sequence generates ids by native sequences of database, e.g. `nextval` on PostgreSQL
+// or `NEXTVAL` on Oracle. Name of sequence is given by formatting Pattern with table name,
+// e.g. `%s_seq`
+type Sequence struct {
+	Pattern string
+}
+
+func (s Sequence) NextID(ctx context.Context, c *Client, tx *sql.Tx, table string) (int64, error) {
+	pattern := s.Pattern
+	if pattern == "" {
+		pattern = "%s_seq"
+	}
+	query := nextSequenceValue(c.dialect, fmt.Sprintf(pattern, table))
+	if query == "" {
+		return 0, fmt.Errorf(`sequence is not supported by dialect %T`, c.dialect)
+	}
+	var id int64
+	err := tx.QueryRowContext(ctx, query).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
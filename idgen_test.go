@@ -0,0 +1,112 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeNextID(t *testing.T) {
+	s, err := NewSnowflake(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := int64(-1)
+	for i := 0; i < 10000; i++ {
+		id, err := s.NextID(context.Background(), nil, nil, "item")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("ids must be increasing, got %d after %d", id, last)
+		}
+		if node := id >> snowflakeSequenceBits & snowflakeMaxNode; node != 5 {
+			t.Fatalf("expected node 5, got %d", node)
+		}
+		last = id
+	}
+}
+
+func TestSnowflakeSequenceRollover(t *testing.T) {
+	s, err := NewSnowflake(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.lastTime = time.Since(s.epoch).Milliseconds()
+	s.sequence = snowflakeMaxSequence
+	lastTime := s.lastTime
+	id, err := s.NextID(context.Background(), nil, nil, "item")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sequence := id & snowflakeMaxSequence; sequence != 0 {
+		t.Errorf("expected sequence 0 after rollover, got %d", sequence)
+	}
+	if ms := id >> (snowflakeNodeBits + snowflakeSequenceBits); ms <= lastTime {
+		t.Errorf("expected time after %d, got %d", lastTime, ms)
+	}
+}
+
+func TestNewSnowflake(t *testing.T) {
+	for _, node := range []int64{-1, snowflakeMaxNode + 1} {
+		if _, err := NewSnowflake(node); err == nil {
+			t.Errorf("expected error of node %d", node)
+		}
+	}
+}
+
+// fixedID always generates the same id
+type fixedID int64
+
+func (g fixedID) NextID(ctx context.Context, c *Client, tx *sql.Tx, table string) (int64, error) {
+	return int64(g), nil
+}
+
+type int32Key struct {
+	Id int32 `column:"id,pk"`
+}
+
+type uint32Key struct {
+	Id uint32 `column:"id,pk"`
+}
+
+type int64Key struct {
+	Id int64 `column:"id,pk"`
+}
+
+func TestAssignID(t *testing.T) {
+	tests := []struct {
+		name  string
+		model interface{}
+		id    int64
+		err   bool
+	}{
+		{"int64 key", &int64Key{}, 1 << 55, false},
+		{"int32 key", &int32Key{}, 1 << 20, false},
+		{"int32 key overflow", &int32Key{}, 1 << 55, true},
+		{"uint32 key", &uint32Key{}, 1 << 31, false},
+		{"uint32 key overflow", &uint32Key{}, 1 << 55, true},
+		{"uint32 key of negative id", &uint32Key{}, -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{idGenerator: fixedID(tt.id)}
+			val := reflect.ValueOf(tt.model).Elem()
+			err := c.assignID(context.Background(), nil, val, "item")
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected error, got id %v", val.Field(0))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual := val.Field(0).Convert(reflect.TypeOf(int64(0))).Int(); actual != tt.id {
+				t.Errorf("expected id %d, got %d", tt.id, actual)
+			}
+		})
+	}
+}
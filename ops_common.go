@@ -43,102 +43,108 @@ func queryTxContext(ctx context.Context, tx *sql.Tx, sql string, params ...inter
 	return stmt, rows, nil
 }
 
-func queryTransaction(ctx context.Context, txFunc func(*sql.Tx) error) (err error) {
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{
-		Isolation: isoLevel,
-		ReadOnly:  readOnly,
-	})
-	if err != nil {
-		return
-	}
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p) // re-throw panic after Rollback
-		} else if err != nil {
-			_ = tx.Rollback() // err is non-nil; don't change it
-		} else {
-			err = tx.Commit() // err is nil; if Commit returns error update err
-			if err != nil {
-				_ = tx.Rollback()
-			}
-		}
-	}()
-	return txFunc(tx)
+func (c *Client) queryTransaction(ctx context.Context, txFunc func(*sql.Tx) error) error {
+	return c.joinTx(ctx, &sql.TxOptions{
+		Isolation: c.isoLevel,
+		ReadOnly:  c.readOnly,
+	}, txFunc)
 }
 
-func execTransaction(ctx context.Context, txFunc func(*sql.Tx) (int64, error)) (i int64, err error) {
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{
-		Isolation: isoLevel,
+func (c *Client) execTransaction(ctx context.Context, txFunc func(*sql.Tx) (int64, error)) (i int64, err error) {
+	err = c.joinTx(ctx, &sql.TxOptions{
+		Isolation: c.isoLevel,
 		ReadOnly:  false,
+	}, func(tx *sql.Tx) error {
+		var e error
+		i, e = txFunc(tx)
+		return e
 	})
-	if err != nil {
-		return
-	}
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p) // re-throw panic after Rollback
-		} else if err != nil {
-			_ = tx.Rollback() // err is non-nil; don't change it
-		} else {
-			err = tx.Commit() // err is nil; if Commit returns error update err
-			if err != nil {
-				_ = tx.Rollback()
-			}
-		}
-	}()
-	return txFunc(tx)
+	return
 }
 
-// ExecuteTxContext executes any statement
+// Execute executes any statement
 func Execute(statement Statement) (int64, error) {
-	return ExecuteContext(context.Background(), statement)
+	return std.Execute(statement)
 }
 
-// ExecuteTxContext executes any statement in a specific context
+// ExecuteContext executes any statement in a specific context
 func ExecuteContext(ctx context.Context, statement Statement) (int64, error) {
-	return execTransaction(ctx, func(t *sql.Tx) (int64, error) {
-		return ExecuteTxContext(ctx, t, statement)
-	})
+	return std.ExecuteContext(ctx, statement)
 }
 
 // ExecuteTxContext executes any statement within a transaction and a specific context
 func ExecuteTxContext(ctx context.Context, tx *sql.Tx, statement Statement) (int64, error) {
-	return execTxContext(ctx, tx, statement.String(), statement.GetParams()...)
+	return std.ExecuteTxContext(ctx, tx, statement)
+}
+
+// Execute executes any statement
+func (c *Client) Execute(statement Statement) (int64, error) {
+	return c.ExecuteContext(context.Background(), statement)
+}
+
+// ExecuteContext executes any statement in a specific context
+func (c *Client) ExecuteContext(ctx context.Context, statement Statement) (int64, error) {
+	return c.execTransaction(ctx, func(t *sql.Tx) (int64, error) {
+		return c.ExecuteTxContext(ctx, t, statement)
+	})
+}
+
+// ExecuteTxContext executes any statement within a transaction and a specific context
+func (c *Client) ExecuteTxContext(ctx context.Context, tx *sql.Tx, statement Statement) (int64, error) {
+	return execTxContext(ctx, tx, statement.build(c.dialect), statement.GetParams()...)
 }
 
 // ExecuteBatch executes a batch of statement
 func ExecuteBatch(statement Statement, args ...map[string]interface{}) (int64, error) {
-	return ExecuteBatchContext(context.Background(), statement)
+	return std.ExecuteBatch(statement, args...)
 }
 
 // ExecuteBatchContext executes a batch of statement within a specific context
 func ExecuteBatchContext(ctx context.Context, statement Statement, args ...map[string]interface{}) (int64, error) {
-	return execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
-		return ExecuteBatchTxContext(ctx, tx, statement)
-	})
+	return std.ExecuteBatchContext(ctx, statement, args...)
 }
 
 // ExecuteBatchTx executes a batch of statement within a transaction
 func ExecuteBatchTx(tx *sql.Tx, statement Statement, args ...map[string]interface{}) (int64, error) {
-	return ExecuteBatchTxContext(context.Background(), tx, statement)
+	return std.ExecuteBatchTx(tx, statement, args...)
 }
 
 // ExecuteBatchTxContext executes a batch of statement within a transaction and a specific context
 func ExecuteBatchTxContext(ctx context.Context, tx *sql.Tx, statement Statement, args ...map[string]interface{}) (int64, error) {
+	return std.ExecuteBatchTxContext(ctx, tx, statement, args...)
+}
+
+// ExecuteBatch executes a batch of statement
+func (c *Client) ExecuteBatch(statement Statement, args ...map[string]interface{}) (int64, error) {
+	return c.ExecuteBatchContext(context.Background(), statement, args...)
+}
+
+// ExecuteBatchContext executes a batch of statement within a specific context
+func (c *Client) ExecuteBatchContext(ctx context.Context, statement Statement, args ...map[string]interface{}) (int64, error) {
+	return c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		return c.ExecuteBatchTxContext(ctx, tx, statement, args...)
+	})
+}
+
+// ExecuteBatchTx executes a batch of statement within a transaction
+func (c *Client) ExecuteBatchTx(tx *sql.Tx, statement Statement, args ...map[string]interface{}) (int64, error) {
+	return c.ExecuteBatchTxContext(context.Background(), tx, statement, args...)
+}
+
+// ExecuteBatchTxContext executes a batch of statement within a transaction and a specific context
+func (c *Client) ExecuteBatchTxContext(ctx context.Context, tx *sql.Tx, statement Statement, args ...map[string]interface{}) (int64, error) {
 	defer func(start time.Time) {
 		if statement.skipLog {
 			return
 		}
 		elapsed := time.Since(start)
-		logger.Infow("xsql - execute a batch of statement", "id", ctx.Value("id"), "elapsed_time", elapsed.Milliseconds(),
+		c.logger.Infow("xsql - execute a batch of statement", "id", ctx.Value("id"), "elapsed_time", elapsed.Milliseconds(),
 			"stmt", statement.RawSql(), "total_item", len(args))
 	}(time.Now())
 	rowsAffected := int64(0)
 	for _, arg := range args {
 		stmt := NewStmt(statement.RawSql()).With(arg)
-		i, err := ExecuteTxContext(ctx, tx, stmt.Get())
+		i, err := c.ExecuteTxContext(ctx, tx, stmt.Get())
 		if err != nil {
 			return 0, err
 		}
@@ -154,24 +160,45 @@ func ExecuteBatchTxContext(ctx context.Context, tx *sql.Tx, statement Statement,
 
 // Count returns the total items in corresponding table of given interface
 func Count(model interface{}) (int64, error) {
-	return CountContext(context.Background(), model)
+	return std.Count(model)
 }
 
-// Count returns the total items in corresponding table of given interface
+// CountContext returns the total items in corresponding table of given interface
 func CountContext(ctx context.Context, model interface{}) (int64, error) {
+	return std.CountContext(ctx, model)
+}
+
+func CountTx(tx *sql.Tx, model interface{}) (int64, error) {
+	return std.CountTx(tx, model)
+}
+
+func CountTxContext(ctx context.Context, tx *sql.Tx, model interface{}) (int64, error) {
+	return std.CountTxContext(ctx, tx, model)
+}
+
+// Count returns the total items in corresponding table of given interface
+func (c *Client) Count(model interface{}) (int64, error) {
+	return c.CountContext(context.Background(), model)
+}
+
+// CountContext returns the total items in corresponding table of given interface
+func (c *Client) CountContext(ctx context.Context, model interface{}) (int64, error) {
 	if model == nil {
 		return 0, fmt.Errorf("given model is nil")
 	}
-	return execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
-		return CountTxContext(ctx, tx, model)
+	return c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		return c.CountTxContext(ctx, tx, model)
 	})
 }
 
-func CountTx(tx *sql.Tx, model interface{}) (int64, error) {
-	return CountTxContext(context.Background(), tx, model)
+func (c *Client) CountTx(tx *sql.Tx, model interface{}) (int64, error) {
+	return c.CountTxContext(context.Background(), tx, model)
 }
 
-func CountTxContext(ctx context.Context, tx *sql.Tx, model interface{}) (int64, error) {
+// CountTxContext returns the total items in corresponding table of given interface within
+// a transaction and a specific context. Soft-deleted items are not counted unless ctx is
+// given by IncludeDeleted
+func (c *Client) CountTxContext(ctx context.Context, tx *sql.Tx, model interface{}) (int64, error) {
 	if model == nil {
 		return 0, fmt.Errorf("given model is nil")
 	}
@@ -179,12 +206,18 @@ func CountTxContext(ctx context.Context, tx *sql.Tx, model interface{}) (int64,
 	if val.Kind() == reflect.Ptr {
 		val = val.Elem()
 	}
+	if err := getMapping(val.Type()).err; err != nil {
+		return 0, err
+	}
 	tableName := getTableName(val)
 
-	sql := fmt.Sprintf(`SELECT count(id) FROM %s WHERE 1=1`, tableName)
+	sql := fmt.Sprintf(`SELECT count(*) FROM %s WHERE 1=1`, tableName)
+	if cond := notDeleted(ctx, val.Type()); cond != "" {
+		sql += ` AND ` + cond
+	}
 	defer func(start time.Time) {
 		elapsed := time.Now().Sub(start)
-		logger.Infow("xsql - count total items in table", "id", ctx.Value("id"),
+		c.logger.Infow("xsql - count total items in table", "id", ctx.Value("id"),
 			"elapsed_time", elapsed.Milliseconds(), "stmt", sql)
 	}(time.Now())
 	stmt, err := tx.PrepareContext(ctx, sql)
@@ -206,20 +239,43 @@ func CountTxContext(ctx context.Context, tx *sql.Tx, model interface{}) (int64,
 	return int64(count), nil
 }
 
-// Count returns the number of item fit with given statement
+// exists reports whether there is any record matching given condition in table
+func (c *Client) exists(ctx context.Context, tx *sql.Tx, tableName, cond string, params map[string]interface{}) (bool, error) {
+	n, err := c.CountWithCondContext(c.ContextWithTx(ctx, tx), NewStmt(`SELECT count(*) FROM`).
+		AppendSql(tableName).
+		AppendSql(`WHERE`).AppendSql(cond).
+		With(params).
+		Get())
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// CountWithCond returns the number of item fit with given statement
 func CountWithCond(statement Statement) (int64, error) {
-	return CountWithCondContext(context.Background(), statement)
+	return std.CountWithCond(statement)
 }
 
 // CountWithCondContext returns the number of item fit with given statement
 func CountWithCondContext(ctx context.Context, statement Statement) (int64, error) {
-	sql := statement.String()
+	return std.CountWithCondContext(ctx, statement)
+}
+
+// CountWithCond returns the number of item fit with given statement
+func (c *Client) CountWithCond(statement Statement) (int64, error) {
+	return c.CountWithCondContext(context.Background(), statement)
+}
+
+// CountWithCondContext returns the number of item fit with given statement
+func (c *Client) CountWithCondContext(ctx context.Context, statement Statement) (int64, error) {
+	sql := statement.build(c.dialect)
 	defer func(start time.Time) {
 		elapsed := time.Now().Sub(start)
-		logger.Infow("xsql - count with condition", "id", ctx.Value("id"),
+		c.logger.Infow("xsql - count with condition", "id", ctx.Value("id"),
 			"elapsed_time", elapsed.Milliseconds(), "stmt", sql, "params", statement.params)
 	}(time.Now())
-	stmt, err := db.PrepareContext(ctx, sql)
+	stmt, err := c.preparer(ctx).PrepareContext(ctx, sql)
 	if err != nil {
 		return 0, err
 	}
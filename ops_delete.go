@@ -10,82 +10,173 @@ import (
 
 // DeleteById deletes specific entity by id
 func DeleteById(model interface{}) (int64, error) {
-	return DeleteByIdContext(context.Background(), model)
+	return std.DeleteById(model)
 }
 
 // DeleteByIdTx deletes specific entity by id within transaction
 func DeleteByIdTx(tx *sql.Tx, model interface{}) (int64, error) {
-	return DeleteByIdTxContext(context.Background(), tx, model)
+	return std.DeleteByIdTx(tx, model)
 }
 
 // DeleteByIdTx deletes specific entity by id in a specific context
 func DeleteByIdContext(ctx context.Context, model interface{}) (int64, error) {
-	return execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
-		return DeleteByIdTxContext(ctx, tx, model)
-	})
+	return std.DeleteByIdContext(ctx, model)
 }
 
 // DeleteByIdTx deletes specific entity by id within transaction and a specific context
 func DeleteByIdTxContext(ctx context.Context, tx *sql.Tx, model interface{}) (int64, error) {
+	return std.DeleteByIdTxContext(ctx, tx, model)
+}
+
+// DeleteById deletes specific entity by id
+func (c *Client) DeleteById(model interface{}) (int64, error) {
+	return c.DeleteByIdContext(context.Background(), model)
+}
+
+// DeleteByIdTx deletes specific entity by id within transaction
+func (c *Client) DeleteByIdTx(tx *sql.Tx, model interface{}) (int64, error) {
+	return c.DeleteByIdTxContext(context.Background(), tx, model)
+}
+
+// DeleteByIdTx deletes specific entity by id in a specific context
+func (c *Client) DeleteByIdContext(ctx context.Context, model interface{}) (int64, error) {
+	return c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		return c.DeleteByIdTxContext(ctx, tx, model)
+	})
+}
+
+// DeleteByIdTx deletes specific entity by id within transaction and a specific context.
+// Id is the primary key declared in column mapping of model, see getPrimaryKeys.
+//
+// If model declares a `softdelete` column, e.g. `column:"deleted_at,softdelete"`, the record
+// is not removed. Instead, the column is set to current time of Clock, both in table and in
+// model if it is a pointer. Record which is already soft-deleted is not affected.
+//
+// If model declares a `version` column, the record is deleted only if its version is still
+// the version of model. Otherwise, it returns ErrStaleObject.
+func (c *Client) DeleteByIdTxContext(ctx context.Context, tx *sql.Tx, model interface{}) (int64, error) {
 	val := reflect.ValueOf(model)
 	if val.Kind() == reflect.Ptr {
 		val = val.Elem()
 	}
+	pkColumns, pkFields, err := getPrimaryKeys(val.Type())
+	if err != nil {
+		return 0, err
+	}
 	tableName := getTableName(val)
+	values := make([]interface{}, len(pkFields))
+	for i, f := range pkFields {
+		values[i] = f.value(val).Interface()
+	}
+	cond, params := keyCondition(pkColumns, values)
+	softDelete, softDeleted := getSoftDelete(val.Type())
+	if softDeleted {
+		cond = fmt.Sprintf(`%s AND %s IS NULL`, cond, softDelete.column)
+	}
+	where := cond
+	version, versioned := getVersion(val.Type())
+	if versioned {
+		v := versionOf(version.value(val))
+		params["xsql_version"] = v
+		params["xsql_next_version"] = v + 1
+		where = fmt.Sprintf(`%s AND %s = :xsql_version`, cond, version.column)
+	}
 
-	var id interface{}
-	for _, k := range [...]string{
-		"id", "Id", "ID",
-	} {
-		field := val.FieldByName(k)
-		if !field.IsValid() {
-			continue
+	var i int64
+	var deleted reflect.Value
+	if softDeleted {
+		val = addressable(val)
+		deleted = reflect.New(softDelete.typ).Elem()
+		setTime(deleted, c.clock.Now())
+		params["xsql_deleted"] = deleted.Interface()
+		sets := fmt.Sprintf(`%s = :xsql_deleted`, softDelete.column)
+		if versioned {
+			sets += fmt.Sprintf(`, %s = :xsql_next_version`, version.column)
 		}
-		id = field.Interface()
-		break
+		i, err = c.UpdateTxContext(ctx, tx, NewStmt(`UPDATE`).AppendSql(tableName).
+			AppendSql(`SET`).AppendSql(sets).
+			AppendSql(`WHERE`).AppendSql(where).
+			With(params).
+			Get())
+	} else {
+		i, err = c.DeleteTxContext(ctx, tx, NewStmt(`DELETE FROM `).AppendSql(tableName).
+			AppendSql(`WHERE`).AppendSql(where).
+			With(params).
+			Get())
 	}
-	if id == nil {
-		return 0, fmt.Errorf(`interface does not have id field`)
+	if err != nil {
+		return 0, err
 	}
-	val.FieldByName("id").Interface()
-
-	return DeleteTxContext(ctx, tx, NewStmt(`DELETE FROM `).AppendSql(tableName).
-		AppendSql(`WHERE id = :id`).
-		With(map[string]interface{}{
-			"id": id,
-		}).
-		Get())
+	if i == 0 && versioned {
+		// missing record is not an error of DeleteById
+		if err = c.staleOrNotFound(ctx, tx, tableName, cond, params); err != ErrNotFound {
+			return 0, err
+		}
+	}
+	if i > 0 && softDeleted {
+		// model is changed only after the record is marked, and it is
+		// restored if the transaction is rolled back later
+		field := softDelete.value(val)
+		c.restoreOnRollback(tx, field)
+		field.Set(deleted)
+		if versioned {
+			field = version.value(val)
+			c.restoreOnRollback(tx, field)
+			setInt(field, versionOf(field)+1)
+		}
+	}
+	return i, nil
 }
 
 // Delete execute a sepecified delete statement
 func Delete(statement Statement) (int64, error) {
-	return DeleteContext(context.Background(), statement)
+	return std.Delete(statement)
 }
 
 // Delete execute a sepecified delete statement
 func DeleteContext(ctx context.Context, statement Statement) (int64, error) {
-	return execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
-		return DeleteTxContext(ctx, tx, statement)
-	})
+	return std.DeleteContext(ctx, statement)
 }
 
 // Delete execute a sepecified delete statement within a transaction
 func DeleteTx(tx *sql.Tx, statement Statement) (int64, error) {
-	return DeleteTxContext(context.Background(), tx, statement)
+	return std.DeleteTx(tx, statement)
 }
 
 // Delete execute a sepecified delete statement within a transaction and a specific context
 func DeleteTxContext(ctx context.Context, tx *sql.Tx, statement Statement) (int64, error) {
+	return std.DeleteTxContext(ctx, tx, statement)
+}
+
+// Delete execute a sepecified delete statement
+func (c *Client) Delete(statement Statement) (int64, error) {
+	return c.DeleteContext(context.Background(), statement)
+}
+
+// Delete execute a sepecified delete statement
+func (c *Client) DeleteContext(ctx context.Context, statement Statement) (int64, error) {
+	return c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		return c.DeleteTxContext(ctx, tx, statement)
+	})
+}
+
+// Delete execute a sepecified delete statement within a transaction
+func (c *Client) DeleteTx(tx *sql.Tx, statement Statement) (int64, error) {
+	return c.DeleteTxContext(context.Background(), tx, statement)
+}
+
+// Delete execute a sepecified delete statement within a transaction and a specific context
+func (c *Client) DeleteTxContext(ctx context.Context, tx *sql.Tx, statement Statement) (int64, error) {
 	defer func(start time.Time) {
 		if statement.skipLog {
 			return
 		}
 		elapsed := time.Now().Sub(start)
-		logger.Infow("xsql - execute delete statement", "id", ctx.Value("id"),
+		c.logger.Infow("xsql - execute delete statement", "id", ctx.Value("id"),
 			"elapsed_time", elapsed.Milliseconds(),
-			"stmt", statement.String(), "params", statement.params)
+			"stmt", statement.build(c.dialect), "params", statement.params)
 	}(time.Now())
-	i, err := ExecuteTxContext(ctx, tx, statement)
+	i, err := c.ExecuteTxContext(ctx, tx, statement)
 	if err != nil {
 		return 0, err
 	}
@@ -11,13 +11,33 @@ import (
 
 // Insert adds given interface into corresponding table
 func Insert(model interface{}) error {
-	return InsertContext(context.Background(), model)
+	return std.Insert(model)
 }
 
 // Insert adds given interface into corresponding table
 func InsertContext(ctx context.Context, model interface{}) error {
-	_, err := execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
-		err := InsertTxContext(ctx, tx, model)
+	return std.InsertContext(ctx, model)
+}
+
+// Insert adds given interface into corresponding table within a transaction
+func InsertTx(tx *sql.Tx, model interface{}) error {
+	return std.InsertTx(tx, model)
+}
+
+// Insert adds given interface into corresponding table within a transaction and context
+func InsertTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
+	return std.InsertTxContext(ctx, tx, model)
+}
+
+// Insert adds given interface into corresponding table
+func (c *Client) Insert(model interface{}) error {
+	return c.InsertContext(context.Background(), model)
+}
+
+// Insert adds given interface into corresponding table
+func (c *Client) InsertContext(ctx context.Context, model interface{}) error {
+	_, err := c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		err := c.InsertTxContext(ctx, tx, model)
 		if err != nil {
 			return 0, err
 		}
@@ -27,12 +47,17 @@ func InsertContext(ctx context.Context, model interface{}) error {
 }
 
 // Insert adds given interface into corresponding table within a transaction
-func InsertTx(tx *sql.Tx, model interface{}) error {
-	return InsertTxContext(context.Background(), tx, model)
+func (c *Client) InsertTx(tx *sql.Tx, model interface{}) error {
+	return c.InsertTxContext(context.Background(), tx, model)
 }
 
-// Insert adds given interface into corresponding table within a transaction and context
-func InsertTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
+// Insert adds given interface into corresponding table within a transaction and context.
+// Columns declared as `readonly` or `noinsert` are not inserted. Columns declared as
+// `omitempty` or `default` are not inserted if their values are zero, so that default
+// values of database are applied. Zero columns declared as `autoCreateTime` or `autoUpdateTime`
+// are set to current time of Clock. If DbOption.IDGenerator is configured and the primary
+// key is zero, a new id is assigned
+func (c *Client) InsertTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
 	start := time.Now()
 	val := reflect.ValueOf(model)
 	if val.Kind() == reflect.Ptr {
@@ -41,14 +66,23 @@ func InsertTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
 	if val.Kind() == reflect.Array || val.Kind() == reflect.Slice {
 		return ErrArgIsArrayOrSlice
 	}
+	val = addressable(val)
+	c.fillTimestamps(val, true, false)
 	tableName := getTableName(val)
-	columns, fieldNames := getColumnsAndFielNames(val.Type())
-	if len(columns) != len(fieldNames) {
-		return fmt.Errorf(`size of column and size of field does not match`)
+	err := c.assignID(ctx, tx, val, tableName)
+	if err != nil {
+		return err
+	}
+	columns, fields, err := insertColumns(val)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf(`model %s does not have any column to insert`, val.Type())
 	}
 	args := make([]interface{}, len(columns))
-	for i, fieldName := range fieldNames {
-		args[i] = val.FieldByName(fieldName).Interface()
+	for i, f := range fields {
+		args[i] = f.value(val).Interface()
 	}
 
 	sqlScript := fmt.Sprintf(`INSERT INTO %s(%s) VALUES (:value)`,
@@ -58,7 +92,7 @@ func InsertTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
 
 	defer func(start time.Time) {
 		elapsed := time.Now().Sub(start)
-		logger.Infow("xsql - execute insert statement", "id", ctx.Value("id"),
+		c.logger.Infow("xsql - execute insert statement", "id", ctx.Value("id"),
 			"elapsed_time", elapsed.Milliseconds(),
 			"stmt", sqlScript, "params", args)
 	}(start)
@@ -66,25 +100,57 @@ func InsertTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
 	insertCmd := NewStmt(sqlScript).With(map[string]interface{}{
 		"value": args,
 	})
-	i, err := ExecuteTxContext(ctx, tx, *insertCmd)
-	if err != nil {
-		return err
-	}
-	if i == 0 {
-		return ErrWrongNumberInserted
+	keyColumn, keyField, ok := generatedKey(val, columns)
+	if ok {
+		err = c.insertReturningKey(ctx, tx, insertCmd.build(c.dialect), insertCmd.GetParams(),
+			keyColumn, keyField)
+		if err != nil {
+			return err
+		}
+	} else {
+		i, err := c.ExecuteTxContext(ctx, tx, *insertCmd)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			return ErrWrongNumberInserted
+		}
 	}
+	c.restoreSnapshotOnRollback(tx, val)
+	takeSnapshot(val, getMapper(val.Type()))
 	return nil
 }
 
 // InsertBatch creates a batch of item in corresponding table of that interface.
 func InsertBatch(model interface{}, batchSize int) error {
-	return InsertBatchContext(context.Background(), model, batchSize)
+	return std.InsertBatch(model, batchSize)
 }
 
 // InsertBatch creates a batch of item in corresponding table of that interface.
 func InsertBatchContext(ctx context.Context, model interface{}, batchSize int) error {
-	_, err := execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
-		err := InsertBatchTxContext(ctx, tx, model, batchSize)
+	return std.InsertBatchContext(ctx, model, batchSize)
+}
+
+// InsertBatch creates a batch of item in corresponding table of that interface within a transaction
+func InsertBatchTx(tx *sql.Tx, model interface{}, batchSize int) error {
+	return std.InsertBatchTx(tx, model, batchSize)
+}
+
+// InsertBatch creates a batch of item in corresponding table of that interface within a transaction
+// and a specific context
+func InsertBatchTxContext(ctx context.Context, tx *sql.Tx, model interface{}, batchSize int) error {
+	return std.InsertBatchTxContext(ctx, tx, model, batchSize)
+}
+
+// InsertBatch creates a batch of item in corresponding table of that interface.
+func (c *Client) InsertBatch(model interface{}, batchSize int) error {
+	return c.InsertBatchContext(context.Background(), model, batchSize)
+}
+
+// InsertBatch creates a batch of item in corresponding table of that interface.
+func (c *Client) InsertBatchContext(ctx context.Context, model interface{}, batchSize int) error {
+	_, err := c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		err := c.InsertBatchTxContext(ctx, tx, model, batchSize)
 		if err != nil {
 			return 0, err
 		}
@@ -94,17 +160,18 @@ func InsertBatchContext(ctx context.Context, model interface{}, batchSize int) e
 }
 
 // InsertBatch creates a batch of item in corresponding table of that interface within a transaction
-func InsertBatchTx(tx *sql.Tx, model interface{}, batchSize int) error {
-	return InsertBatchTxContext(context.Background(), tx, model, batchSize)
+func (c *Client) InsertBatchTx(tx *sql.Tx, model interface{}, batchSize int) error {
+	return c.InsertBatchTxContext(context.Background(), tx, model, batchSize)
 }
 
 // InsertBatch creates a batch of item in corresponding table of that interface within a transaction
-// and a specific context
-func InsertBatchTxContext(ctx context.Context, tx *sql.Tx, model interface{}, batchSize int) error {
+// and a specific context. Items which omit different columns, see InsertTxContext, are inserted
+// by different statements. Items whose keys are generated by database are inserted one by one,
+// so that each key is written back into its own item
+func (c *Client) InsertBatchTxContext(ctx context.Context, tx *sql.Tx, model interface{}, batchSize int) error {
 	start := time.Now()
 	val := reflect.ValueOf(model)
 	if val.Kind() != reflect.Array && val.Kind() != reflect.Slice {
-		_ = tx.Rollback()
 		return ErrArgNotArrayAndSlice
 	}
 
@@ -112,51 +179,375 @@ func InsertBatchTxContext(ctx context.Context, tx *sql.Tx, model interface{}, ba
 		return nil
 	}
 
-	fe := val.Index(0)
+	fe := reflect.Indirect(val.Index(0))
 	tableName := getTableName(fe)
-	columns, fieldNames := getColumnsAndFielNames(fe.Type())
-	if len(columns) != len(fieldNames) {
-		return fmt.Errorf(`size of column and size of field does not match`)
-	}
 
-	insertedBatches := chunk(val, batchSize)
-	sqlColumns := strings.Join(columns, ",")
+	// items omitting different columns can not be inserted by the same statement,
+	// so that they are grouped by their inserted columns
+	groups := make(map[string][]reflect.Value)
+	var groupKeys []string
+	for i := 0; i < val.Len(); i++ {
+		v := addressable(reflect.Indirect(val.Index(i)))
+		c.fillTimestamps(v, true, false)
+		err := c.assignID(ctx, tx, v, tableName)
+		if err != nil {
+			return err
+		}
+		columns, _, err := insertColumns(v)
+		if err != nil {
+			return err
+		}
+		key := strings.Join(columns, ",")
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], v)
+	}
 
 	defer func(start time.Time) {
 		elapsed := time.Now().Sub(start)
-		logger.Infow("xsql - execute insert-batch statement", "id", ctx.Value("id"),
+		c.logger.Infow("xsql - execute insert-batch statement", "id", ctx.Value("id"),
 			"elapsed_time", elapsed.Milliseconds(),
-			"stmt", fmt.Sprintf(`INSERT INTO %s(%s) VALUES (:value)`, tableName, sqlColumns),
+			"stmt", fmt.Sprintf(`INSERT INTO %s(%s) VALUES (:value)`, tableName, groupKeys[0]),
 			"total_item", val.Len(), "batch_size", batchSize)
 	}(start)
 
-	numberOfField := len(fieldNames)
-	paramPlaceHolder := strRepeat("(", ")", "%s", ",", len(columns))
-	sqlParams := strRepeat("", "", paramPlaceHolder, ",", batchSize)
-	for _, batch := range insertedBatches {
+	for _, sqlColumns := range groupKeys {
+		items := groups[sqlColumns]
+		columns, fields, _ := insertColumns(items[0])
+		if len(fields) == 0 {
+			return fmt.Errorf(`model %s does not have any column to insert`, fe.Type())
+		}
+		keyColumn, _, hasKey := generatedKey(items[0], columns)
+		size := batchSize
+		returnKey := hasKey && keyReturning(c.dialect) != ReturningNone
+		if returnKey {
+			// databases do not guarantee which generated key belongs to which row of
+			// a multi-row insert, so rows are inserted one by one in order to read their keys
+			size = 1
+		}
+		numberOfField := len(fields)
+		for _, batch := range chunkValues(items, size) {
+			values := make([]interface{}, len(batch)*numberOfField)
+			for i, v := range batch {
+				for j, f := range fields {
+					values[i*numberOfField+j] = f.value(v).Interface()
+				}
+			}
 
-		values := make([]interface{}, len(batch)*numberOfField)
-		for i, v := range batch {
-			for j, fieldName := range fieldNames {
-				values[i*numberOfField+j] = v.FieldByName(fieldName).Interface()
+			realInsertSql := fmt.Sprintf(`INSERT INTO %s(%s) VALUES %s`,
+				tableName,
+				sqlColumns,
+				valuesPlaceHolder(c.dialect, numberOfField, len(batch)),
+			)
+			if returnKey {
+				_, key, _ := generatedKey(batch[0], columns)
+				err := c.insertReturningKey(ctx, tx, realInsertSql, values, keyColumn, key)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			i, err := execTxContext(ctx, tx, realInsertSql, values...)
+			if err != nil {
+				return err
+			}
+			if int(i) != len(batch) {
+				return ErrWrongNumberInserted
 			}
 		}
+	}
+	return nil
+}
+
+// generatedKey returns column and field of primary key of given struct value if its value
+// is generated by database. It is a single integer key which is omitted from inserted columns,
+// e.g. by `autoincrement` option
+func generatedKey(val reflect.Value, insertedColumns []string) (string, reflect.Value, bool) {
+	pkColumns, pkFields, err := getPrimaryKeys(val.Type())
+	if err != nil || len(pkColumns) != 1 {
+		return "", reflect.Value{}, false
+	}
+	for _, column := range insertedColumns {
+		if column == pkColumns[0] {
+			return "", reflect.Value{}, false
+		}
+	}
+	field := pkFields[0].value(val)
+	switch field.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return pkColumns[0], field, true
+	}
+	return "", reflect.Value{}, false
+}
 
-		if batchSize != len(batch) {
-			sqlParams = strRepeat("", "", paramPlaceHolder, ",", len(batch))
+// insertReturningKey executes given insert statement of a single row then writes
+// the key generated by database into given field
+func (c *Client) insertReturningKey(ctx context.Context, tx *sql.Tx, query string, params []interface{},
+	keyColumn string, key reflect.Value) error {
+	// key is reset if the transaction is rolled back later
+	c.restoreOnRollback(tx, key)
+	switch keyReturning(c.dialect) {
+	case ReturningClause:
+		stmt, rows, err := queryTxContext(ctx, tx, fmt.Sprintf(`%s RETURNING %s`, query, keyColumn), params...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+			_ = stmt.Close()
+		}()
+		if !rows.Next() {
+			if err = rows.Err(); err != nil {
+				return err
+			}
+			return ErrWrongNumberInserted
+		}
+		err = rows.Scan(key.Addr().Interface())
+		if err != nil {
+			return err
+		}
+		if rows.Next() {
+			return ErrWrongNumberInserted
+		}
+		return rows.Err()
+	case ReturningLastInsertId:
+		rs, err := tx.ExecContext(ctx, query, params...)
+		if err != nil {
+			return err
+		}
+		i, err := rs.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if i != 1 {
+			return ErrWrongNumberInserted
 		}
-		realInsertSql := fmt.Sprintf(`INSERT INTO %s(%s) VALUES %s`,
-			tableName,
-			sqlColumns,
-			fmt.Sprintf(sqlParams, strToIntf(dialect.Parameterizie(len(values)))...),
-		)
-		i, err := execTxContext(ctx, tx, realInsertSql, values...)
+		id, err := rs.LastInsertId()
 		if err != nil {
 			return err
 		}
-		if int(i) != len(batch) {
+		setInt(key, id)
+		return nil
+	case ReturningInto:
+		var id int64
+		params = append(params, sql.Out{Dest: &id})
+		placeHolders := c.dialect.Parameterizie(len(params))
+		i, err := execTxContext(ctx, tx, fmt.Sprintf(`%s RETURNING %s INTO %s`,
+			query, keyColumn, placeHolders[len(placeHolders)-1]), params...)
+		if err != nil {
+			return err
+		}
+		if i != 1 {
 			return ErrWrongNumberInserted
 		}
+		setInt(key, id)
+		return nil
+	}
+	i, err := execTxContext(ctx, tx, query, params...)
+	if err != nil {
+		return err
+	}
+	if i != 1 {
+		return ErrWrongNumberInserted
+	}
+	return nil
+}
+
+// Upsert adds given interface into corresponding table, or updates existing record
+// which conflicts with it on conflictColumns. See Client.UpsertTxContext
+func Upsert(model interface{}, conflictColumns, updateColumns []string) error {
+	return std.Upsert(model, conflictColumns, updateColumns)
+}
+
+// UpsertContext adds given interface into corresponding table, or updates existing record
+// which conflicts with it on conflictColumns. See Client.UpsertTxContext
+func UpsertContext(ctx context.Context, model interface{}, conflictColumns, updateColumns []string) error {
+	return std.UpsertContext(ctx, model, conflictColumns, updateColumns)
+}
+
+// UpsertTx adds given interface into corresponding table, or updates existing record
+// which conflicts with it on conflictColumns within a transaction. See Client.UpsertTxContext
+func UpsertTx(tx *sql.Tx, model interface{}, conflictColumns, updateColumns []string) error {
+	return std.UpsertTx(tx, model, conflictColumns, updateColumns)
+}
+
+// UpsertTxContext adds given interface into corresponding table, or updates existing record
+// which conflicts with it on conflictColumns within a transaction and context. See Client.UpsertTxContext
+func UpsertTxContext(ctx context.Context, tx *sql.Tx, model interface{}, conflictColumns, updateColumns []string) error {
+	return std.UpsertTxContext(ctx, tx, model, conflictColumns, updateColumns)
+}
+
+// Upsert adds given interface into corresponding table, or updates existing record
+// which conflicts with it on conflictColumns. See Client.UpsertTxContext
+func (c *Client) Upsert(model interface{}, conflictColumns, updateColumns []string) error {
+	return c.UpsertContext(context.Background(), model, conflictColumns, updateColumns)
+}
+
+// UpsertContext adds given interface into corresponding table, or updates existing record
+// which conflicts with it on conflictColumns. See Client.UpsertTxContext
+func (c *Client) UpsertContext(ctx context.Context, model interface{}, conflictColumns, updateColumns []string) error {
+	_, err := c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		err := c.UpsertTxContext(ctx, tx, model, conflictColumns, updateColumns)
+		if err != nil {
+			return 0, err
+		}
+		return 1, nil
+	})
+	return err
+}
+
+// UpsertTx adds given interface into corresponding table, or updates existing record
+// which conflicts with it on conflictColumns within a transaction. See Client.UpsertTxContext
+func (c *Client) UpsertTx(tx *sql.Tx, model interface{}, conflictColumns, updateColumns []string) error {
+	return c.UpsertTxContext(context.Background(), tx, model, conflictColumns, updateColumns)
+}
+
+// UpsertTxContext adds given interface into corresponding table, or updates existing record
+// which conflicts with it on conflictColumns within a transaction and context.
+//
+// conflictColumns must be a primary key or an unique key of table. If updateColumns is empty,
+// all columns except conflictColumns and columns declared as `noupdate` or `autoCreateTime`
+// are updated.
+// Columns declared as `readonly` or `noinsert` are never written.
+func (c *Client) UpsertTxContext(ctx context.Context, tx *sql.Tx, model interface{}, conflictColumns, updateColumns []string) error {
+	val := reflect.ValueOf(model)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() == reflect.Array || val.Kind() == reflect.Slice {
+		return ErrArgIsArrayOrSlice
+	}
+	return c.upsertTxContext(ctx, tx, [][]reflect.Value{{val}}, 1, conflictColumns, updateColumns)
+}
+
+// UpsertBatch adds or updates a batch of item in corresponding table of that interface.
+// See Client.UpsertTxContext
+func UpsertBatch(model interface{}, batchSize int, conflictColumns, updateColumns []string) error {
+	return std.UpsertBatch(model, batchSize, conflictColumns, updateColumns)
+}
+
+// UpsertBatchContext adds or updates a batch of item in corresponding table of that interface.
+// See Client.UpsertTxContext
+func UpsertBatchContext(ctx context.Context, model interface{}, batchSize int, conflictColumns, updateColumns []string) error {
+	return std.UpsertBatchContext(ctx, model, batchSize, conflictColumns, updateColumns)
+}
+
+// UpsertBatchTx adds or updates a batch of item in corresponding table of that interface
+// within a transaction. See Client.UpsertTxContext
+func UpsertBatchTx(tx *sql.Tx, model interface{}, batchSize int, conflictColumns, updateColumns []string) error {
+	return std.UpsertBatchTx(tx, model, batchSize, conflictColumns, updateColumns)
+}
+
+// UpsertBatchTxContext adds or updates a batch of item in corresponding table of that interface
+// within a transaction and a specific context. See Client.UpsertTxContext
+func UpsertBatchTxContext(ctx context.Context, tx *sql.Tx, model interface{}, batchSize int, conflictColumns, updateColumns []string) error {
+	return std.UpsertBatchTxContext(ctx, tx, model, batchSize, conflictColumns, updateColumns)
+}
+
+// UpsertBatch adds or updates a batch of item in corresponding table of that interface.
+// See Client.UpsertTxContext
+func (c *Client) UpsertBatch(model interface{}, batchSize int, conflictColumns, updateColumns []string) error {
+	return c.UpsertBatchContext(context.Background(), model, batchSize, conflictColumns, updateColumns)
+}
+
+// UpsertBatchContext adds or updates a batch of item in corresponding table of that interface.
+// See Client.UpsertTxContext
+func (c *Client) UpsertBatchContext(ctx context.Context, model interface{}, batchSize int, conflictColumns, updateColumns []string) error {
+	_, err := c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		err := c.UpsertBatchTxContext(ctx, tx, model, batchSize, conflictColumns, updateColumns)
+		if err != nil {
+			return 0, err
+		}
+		return 0, nil
+	})
+	return err
+}
+
+// UpsertBatchTx adds or updates a batch of item in corresponding table of that interface
+// within a transaction. See Client.UpsertTxContext
+func (c *Client) UpsertBatchTx(tx *sql.Tx, model interface{}, batchSize int, conflictColumns, updateColumns []string) error {
+	return c.UpsertBatchTxContext(context.Background(), tx, model, batchSize, conflictColumns, updateColumns)
+}
+
+// UpsertBatchTxContext adds or updates a batch of item in corresponding table of that interface
+// within a transaction and a specific context. See Client.UpsertTxContext
+func (c *Client) UpsertBatchTxContext(ctx context.Context, tx *sql.Tx, model interface{}, batchSize int, conflictColumns, updateColumns []string) error {
+	val := reflect.ValueOf(model)
+	if val.Kind() != reflect.Array && val.Kind() != reflect.Slice {
+		return ErrArgNotArrayAndSlice
+	}
+	if val.Len() == 0 {
+		return nil
+	}
+	return c.upsertTxContext(ctx, tx, chunk(val, batchSize), batchSize, conflictColumns, updateColumns)
+}
+
+// upsertTxContext executes one upsert statement for each batch
+func (c *Client) upsertTxContext(ctx context.Context, tx *sql.Tx, batches [][]reflect.Value, batchSize int,
+	conflictColumns, updateColumns []string) error {
+	start := time.Now()
+	if len(conflictColumns) == 0 {
+		return fmt.Errorf(`conflict columns are not specified`)
+	}
+	fe := reflect.Indirect(batches[0][0])
+	tableName := getTableName(fe)
+	tm := getMapping(fe.Type())
+	if tm.err != nil {
+		return tm.err
+	}
+	allFields := tm.fields
+	var columns []string
+	var fields []*fieldMapping
+	for _, f := range allFields {
+		// soft-delete marker is left NULL for new records
+		if f.options.insertable() && !f.options.has("softdelete") {
+			columns = append(columns, f.column)
+			fields = append(fields, f)
+		}
+	}
+	if len(updateColumns) == 0 {
+		conflicts := make(map[string]bool, len(conflictColumns))
+		for _, column := range conflictColumns {
+			conflicts[column] = true
+		}
+		for _, f := range allFields {
+			if !conflicts[f.column] && f.options.insertable() && f.options.updatable() &&
+				!f.options.has("autoCreateTime") {
+				updateColumns = append(updateColumns, f.column)
+			}
+		}
+	}
+
+	total := 0
+	for _, batch := range batches {
+		total += len(batch)
+	}
+	defer func(start time.Time) {
+		elapsed := time.Now().Sub(start)
+		c.logger.Infow("xsql - execute upsert statement", "id", ctx.Value("id"),
+			"elapsed_time", elapsed.Milliseconds(),
+			"stmt", upsert(c.dialect, tableName, columns, conflictColumns, updateColumns, 1),
+			"total_item", total, "batch_size", batchSize)
+	}(start)
+
+	numberOfField := len(fields)
+	for _, batch := range batches {
+		values := make([]interface{}, len(batch)*numberOfField)
+		for i, v := range batch {
+			v = addressable(reflect.Indirect(v))
+			// record may be either inserted or updated
+			c.fillTimestamps(v, true, true)
+			for j, f := range fields {
+				values[i*numberOfField+j] = f.value(v).Interface()
+			}
+		}
+		upsertSql := upsert(c.dialect, tableName, columns, conflictColumns, updateColumns, len(batch))
+		_, err := execTxContext(ctx, tx, upsertSql, values...)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -5,25 +5,48 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 )
 
 // Query return a slice of records
 func Query(statement Statement, output interface{}) error {
-	return QueryContext(context.Background(), statement, output)
+	return std.Query(statement, output)
 }
 
 // Query return a slice of records
 func QueryContext(ctx context.Context, statement Statement, output interface{}) error {
-	return queryTransaction(ctx, func(tx *sql.Tx) error {
-		return QueryTxContext(ctx, tx, statement, output)
-	})
+	return std.QueryContext(ctx, statement, output)
 }
 
 // Query return a slice of records. By giving a specific transaction, it may get a latest records
 // which are inserted/updated in previous actions during given transaction
 func QueryTx(tx *sql.Tx, statement Statement, output interface{}) error {
-	return QueryTxContext(context.Background(), tx, statement, output)
+	return std.QueryTx(tx, statement, output)
+}
+
+// Query return a slice of records. By giving a specific transaction, it may get a latest records
+// which are inserted/updated in previous actions during given transaction.
+func QueryTxContext(ctx context.Context, tx *sql.Tx, statement Statement, output interface{}) error {
+	return std.QueryTxContext(ctx, tx, statement, output)
+}
+
+// Query return a slice of records
+func (c *Client) Query(statement Statement, output interface{}) error {
+	return c.QueryContext(context.Background(), statement, output)
+}
+
+// Query return a slice of records
+func (c *Client) QueryContext(ctx context.Context, statement Statement, output interface{}) error {
+	return c.queryTransaction(ctx, func(tx *sql.Tx) error {
+		return c.QueryTxContext(ctx, tx, statement, output)
+	})
+}
+
+// Query return a slice of records. By giving a specific transaction, it may get a latest records
+// which are inserted/updated in previous actions during given transaction
+func (c *Client) QueryTx(tx *sql.Tx, statement Statement, output interface{}) error {
+	return c.QueryTxContext(context.Background(), tx, statement, output)
 }
 
 // Query return a slice of records. By giving a specific transaction, it may get a latest records
@@ -32,7 +55,13 @@ func QueryTx(tx *sql.Tx, statement Statement, output interface{}) error {
 // The provided context will be used for the preparation of the context, not
 // for the execution of the returned statement. The returned statement
 // will run in the transaction context.
-func QueryTxContext(ctx context.Context, tx *sql.Tx, statement Statement, output interface{}) error {
+//
+// Output is a pointer to a slice whose items are either
+//   - structs mapped by `column` tag, or pointers to them, e.g. []ExampleTable or []*ExampleTable
+//   - models implementing RowScanner which decode records by themselves
+//   - maps from column to value, e.g. []map[string]interface{}
+//   - single values of one-column queries, e.g. []int64 or []string
+func (c *Client) QueryTxContext(ctx context.Context, tx *sql.Tx, statement Statement, output interface{}) error {
 	start := time.Now()
 	valType := reflect.TypeOf(output)
 	if valType.Kind() == reflect.Ptr {
@@ -44,19 +73,19 @@ func QueryTxContext(ctx context.Context, tx *sql.Tx, statement Statement, output
 	}
 
 	valType = valType.Elem()
-	rm := getMapper(valType)
+	rm := c.mapper(valType)
 	val := reflect.ValueOf(output)
 	if val.Kind() == reflect.Ptr {
 		val = val.Elem()
 	}
 
-	sql := statement.String()
+	sql := statement.build(c.dialect)
 	defer func(start time.Time) {
 		if statement.skipLog {
 			return
 		}
 		elapsed := time.Now().Sub(start)
-		logger.Infow("xsql - execute query statement", "id", ctx.Value("id"),
+		c.logger.Infow("xsql - execute query statement", "id", ctx.Value("id"),
 			"elapsed_time", elapsed.Milliseconds(),
 			"stmt", sql, "params", statement.params)
 	}(start)
@@ -71,22 +100,16 @@ func QueryTxContext(ctx context.Context, tx *sql.Tx, statement Statement, output
 	}()
 
 	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
 	for rows.Next() {
-		ptr := reflect.New(valType)
-		elem := ptr.Elem()
-		args := make([]interface{}, len(cols))
-		for i, v := range cols {
-			fieldName, ok := rm.Col2Field[v]
-			if !ok {
-				return fmt.Errorf(`no such field mapped to column %s`, v)
-			}
-			args[i] = elem.FieldByName(fieldName).Addr().Interface()
-		}
-		e := rows.Scan(args...)
+		elem := reflect.New(valType).Elem()
+		e := scanRow(rows, cols, rm, elem)
 		if e != nil {
 			return e
 		}
-		val.Set(reflect.Append(val, ptr.Elem()))
+		val.Set(reflect.Append(val, elem))
 	}
 
 	err = rows.Err()
@@ -98,25 +121,48 @@ func QueryTxContext(ctx context.Context, tx *sql.Tx, statement Statement, output
 
 // QueryOne will returns an item fit given statement if it exist. Otherwise, it return ErrNotFound
 func QueryOne(statement Statement, output interface{}) error {
-	return QueryOneContext(context.Background(), statement, output)
+	return std.QueryOne(statement, output)
 }
 
 // QueryOne will returns an item fit given statement if it exist. Otherwise, it return ErrNotFound
 func QueryOneContext(ctx context.Context, statement Statement, output interface{}) error {
-	return queryTransaction(ctx, func(tx *sql.Tx) error {
-		return QueryOneTxContext(ctx, tx, statement, output)
-	})
+	return std.QueryOneContext(ctx, statement, output)
 }
 
 // QueryOne will returns an item fit given statement if it exist. Otherwise, it return ErrNotFound.
 // This action is excuted within a transaction
 func QueryOneTx(tx *sql.Tx, statement Statement, output interface{}) error {
-	return QueryOneTxContext(context.Background(), tx, statement, output)
+	return std.QueryOneTx(tx, statement, output)
 }
 
 // QueryOne will returns an item fit given statement if it exist. Otherwise, it return ErrNotFound.
 // This action is excuted within a transaction and a specific context
 func QueryOneTxContext(ctx context.Context, tx *sql.Tx, statement Statement, output interface{}) error {
+	return std.QueryOneTxContext(ctx, tx, statement, output)
+}
+
+// QueryOne will returns an item fit given statement if it exist. Otherwise, it return ErrNotFound
+func (c *Client) QueryOne(statement Statement, output interface{}) error {
+	return c.QueryOneContext(context.Background(), statement, output)
+}
+
+// QueryOne will returns an item fit given statement if it exist. Otherwise, it return ErrNotFound
+func (c *Client) QueryOneContext(ctx context.Context, statement Statement, output interface{}) error {
+	return c.queryTransaction(ctx, func(tx *sql.Tx) error {
+		return c.QueryOneTxContext(ctx, tx, statement, output)
+	})
+}
+
+// QueryOne will returns an item fit given statement if it exist. Otherwise, it return ErrNotFound.
+// This action is excuted within a transaction
+func (c *Client) QueryOneTx(tx *sql.Tx, statement Statement, output interface{}) error {
+	return c.QueryOneTxContext(context.Background(), tx, statement, output)
+}
+
+// QueryOne will returns an item fit given statement if it exist. Otherwise, it return ErrNotFound.
+// This action is excuted within a transaction and a specific context. Output is a pointer to
+// a value of any type which is supported as item by QueryTxContext, e.g. *ExampleTable or *int64
+func (c *Client) QueryOneTxContext(ctx context.Context, tx *sql.Tx, statement Statement, output interface{}) error {
 	start := time.Now()
 	valType := reflect.TypeOf(output)
 	if valType.Kind() == reflect.Ptr {
@@ -127,15 +173,15 @@ func QueryOneTxContext(ctx context.Context, tx *sql.Tx, statement Statement, out
 		return fmt.Errorf("input is either array or slice")
 	}
 
-	rm := getMapper(valType)
+	rm := c.mapper(valType)
 
-	sql := statement.String()
+	sql := statement.build(c.dialect)
 	defer func(start time.Time) {
 		if statement.skipLog {
 			return
 		}
 		elapsed := time.Now().Sub(start)
-		logger.Infow("xsql - execute query-one statement", "id", ctx.Value("id"),
+		c.logger.Infow("xsql - execute query-one statement", "id", ctx.Value("id"),
 			"elapsed_time", elapsed.Milliseconds(),
 			"stmt", sql, "params", statement.params)
 	}(start)
@@ -156,15 +202,7 @@ func QueryOneTxContext(ctx context.Context, tx *sql.Tx, statement Statement, out
 	numberOfRows := 0
 	for rows.Next() {
 		elem := reflect.ValueOf(output).Elem()
-		args := make([]interface{}, len(cols))
-		for i, v := range cols {
-			fieldName, ok := rm.Col2Field[v]
-			if !ok {
-				return fmt.Errorf(`no such field mapped to column %s`, v)
-			}
-			args[i] = elem.FieldByName(fieldName).Addr().Interface()
-		}
-		e := rows.Scan(args...)
+		e := scanRow(rows, cols, rm, elem)
 		if e != nil {
 			return e
 		}
@@ -182,3 +220,76 @@ func QueryOneTxContext(ctx context.Context, tx *sql.Tx, statement Statement, out
 	}
 	return nil
 }
+
+// FindById finds the record having given id and stores it into output.
+// If there is no such record, it returns ErrNotFound
+func FindById(id interface{}, output interface{}) error {
+	return std.FindById(id, output)
+}
+
+// FindByIdContext finds the record having given id in a specific context
+func FindByIdContext(ctx context.Context, id interface{}, output interface{}) error {
+	return std.FindByIdContext(ctx, id, output)
+}
+
+// FindByIdTx finds the record having given id within a transaction
+func FindByIdTx(tx *sql.Tx, id interface{}, output interface{}) error {
+	return std.FindByIdTx(tx, id, output)
+}
+
+// FindByIdTxContext finds the record having given id within a transaction and a specific context
+func FindByIdTxContext(ctx context.Context, tx *sql.Tx, id interface{}, output interface{}) error {
+	return std.FindByIdTxContext(ctx, tx, id, output)
+}
+
+// FindById finds the record having given id and stores it into output.
+// If there is no such record, it returns ErrNotFound
+func (c *Client) FindById(id interface{}, output interface{}) error {
+	return c.FindByIdContext(context.Background(), id, output)
+}
+
+// FindByIdContext finds the record having given id in a specific context
+func (c *Client) FindByIdContext(ctx context.Context, id interface{}, output interface{}) error {
+	return c.queryTransaction(ctx, func(tx *sql.Tx) error {
+		return c.FindByIdTxContext(ctx, tx, id, output)
+	})
+}
+
+// FindByIdTx finds the record having given id within a transaction
+func (c *Client) FindByIdTx(tx *sql.Tx, id interface{}, output interface{}) error {
+	return c.FindByIdTxContext(context.Background(), tx, id, output)
+}
+
+// FindByIdTxContext finds the record having given id within a transaction and a specific context.
+// Output must be a pointer to a struct whose primary key is declared in its column mapping.
+// Table is given by TableName method of output. If primary key is a composite key, id must be
+// a slice of values of key columns in order of declaration. Soft-deleted record is not found
+// unless ctx is given by IncludeDeleted
+func (c *Client) FindByIdTxContext(ctx context.Context, tx *sql.Tx, id interface{}, output interface{}) error {
+	val := reflect.ValueOf(output)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return fmt.Errorf("output is not a pointer")
+	}
+	val = val.Elem()
+	pkColumns, _, err := getPrimaryKeys(val.Type())
+	if err != nil {
+		return err
+	}
+	values, err := keyValues(id, len(pkColumns))
+	if err != nil {
+		return err
+	}
+	cond, params := keyCondition(pkColumns, values)
+	if deleted := notDeleted(ctx, val.Type()); deleted != "" {
+		cond += ` AND ` + deleted
+	}
+	columns, _, err := getColumnsAndFielNames(val.Type())
+	if err != nil {
+		return err
+	}
+	return c.QueryOneTxContext(ctx, tx, NewStmt(`SELECT`).AppendSql(strings.Join(columns, ",")).
+		AppendSql(`FROM`).AppendSql(getTableName(val)).
+		AppendSql(`WHERE`).AppendSql(cond).
+		With(params).
+		Get(), output)
+}
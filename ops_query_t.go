@@ -0,0 +1,90 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// QueryT returns a slice of records of type T by using the default client
+func QueryT[T any](ctx context.Context, statement Statement) ([]T, error) {
+	return ClientQueryT[T](ctx, std, statement)
+}
+
+// QueryTxT returns a slice of records of type T within a transaction by using the default client
+func QueryTxT[T any](ctx context.Context, tx *sql.Tx, statement Statement) ([]T, error) {
+	return ClientQueryTxT[T](ctx, std, tx, statement)
+}
+
+// QueryOneT returns a record of type T by using the default client.
+// If there is no record, it returns ErrNotFound
+func QueryOneT[T any](ctx context.Context, statement Statement) (T, error) {
+	return ClientQueryOneT[T](ctx, std, statement)
+}
+
+// QueryOneTxT returns a record of type T within a transaction by using the default client.
+// If there is no record, it returns ErrNotFound
+func QueryOneTxT[T any](ctx context.Context, tx *sql.Tx, statement Statement) (T, error) {
+	return ClientQueryOneTxT[T](ctx, std, tx, statement)
+}
+
+// ClientQueryT returns a slice of records of type T by using given client
+func ClientQueryT[T any](ctx context.Context, c *Client, statement Statement) ([]T, error) {
+	var rs []T
+	err := c.QueryContext(ctx, statement, &rs)
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// ClientQueryTxT returns a slice of records of type T within a transaction by using given client
+func ClientQueryTxT[T any](ctx context.Context, c *Client, tx *sql.Tx, statement Statement) ([]T, error) {
+	var rs []T
+	err := c.QueryTxContext(ctx, tx, statement, &rs)
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// ClientQueryOneT returns a record of type T by using given client.
+// If there is no record, it returns ErrNotFound
+func ClientQueryOneT[T any](ctx context.Context, c *Client, statement Statement) (T, error) {
+	var rs T
+	err := c.QueryOneContext(ctx, statement, &rs)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return rs, nil
+}
+
+// ClientQueryOneTxT returns a record of type T within a transaction by using given client.
+// If there is no record, it returns ErrNotFound
+func ClientQueryOneTxT[T any](ctx context.Context, c *Client, tx *sql.Tx, statement Statement) (T, error) {
+	var rs T
+	err := c.QueryOneTxContext(ctx, tx, statement, &rs)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return rs, nil
+}
+
+// FindByIdT returns the record of type T having given id by using the default client.
+// If there is no such record, it returns ErrNotFound
+func FindByIdT[T any](ctx context.Context, id interface{}) (T, error) {
+	return ClientFindByIdT[T](ctx, std, id)
+}
+
+// ClientFindByIdT returns the record of type T having given id by using given client.
+// If there is no such record, it returns ErrNotFound
+func ClientFindByIdT[T any](ctx context.Context, c *Client, id interface{}) (T, error) {
+	var rs T
+	err := c.FindByIdContext(ctx, id, &rs)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return rs, nil
+}
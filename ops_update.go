@@ -3,38 +3,61 @@ package xsql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 )
 
 // Update execute a sepecified update statement
 func Update(statement Statement) (int64, error) {
-	return UpdateContext(context.Background(), statement)
+	return std.Update(statement)
 }
 
 // Update execute a sepecified update statement
 func UpdateContext(ctx context.Context, statement Statement) (int64, error) {
-	return execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
-		return UpdateTxContext(ctx, tx, statement)
-	})
+	return std.UpdateContext(ctx, statement)
 }
 
 // Update execute a sepecified update statement within a transaction
 func UpdateTx(tx *sql.Tx, statement Statement) (int64, error) {
-	return UpdateTxContext(context.Background(), tx, statement)
+	return std.UpdateTx(tx, statement)
 }
 
 // Update execute a sepecified update statement within a transaction and an specific context
 func UpdateTxContext(ctx context.Context, tx *sql.Tx, statement Statement) (int64, error) {
+	return std.UpdateTxContext(ctx, tx, statement)
+}
+
+// Update execute a sepecified update statement
+func (c *Client) Update(statement Statement) (int64, error) {
+	return c.UpdateContext(context.Background(), statement)
+}
+
+// Update execute a sepecified update statement
+func (c *Client) UpdateContext(ctx context.Context, statement Statement) (int64, error) {
+	return c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		return c.UpdateTxContext(ctx, tx, statement)
+	})
+}
+
+// Update execute a sepecified update statement within a transaction
+func (c *Client) UpdateTx(tx *sql.Tx, statement Statement) (int64, error) {
+	return c.UpdateTxContext(context.Background(), tx, statement)
+}
+
+// Update execute a sepecified update statement within a transaction and an specific context
+func (c *Client) UpdateTxContext(ctx context.Context, tx *sql.Tx, statement Statement) (int64, error) {
 	defer func(start time.Time) {
 		if statement.skipLog {
 			return
 		}
 		elapsed := time.Since(start)
-		logger.Infow("xsql - execute update statement", "id", ctx.Value("id"), "elapsed_time", elapsed.Milliseconds(),
-			"stmt", statement.String(), "params", statement.params)
+		c.logger.Infow("xsql - execute update statement", "id", ctx.Value("id"), "elapsed_time", elapsed.Milliseconds(),
+			"stmt", statement.build(c.dialect), "params", statement.params)
 	}(time.Now())
 
-	i, err := ExecuteTxContext(ctx, tx, statement)
+	i, err := c.ExecuteTxContext(ctx, tx, statement)
 	if err != nil {
 		return 0, err
 	}
@@ -47,44 +70,304 @@ func UpdateTxContext(ctx context.Context, tx *sql.Tx, statement Statement) (int6
 }
 
 func Updates(statement Statement, args ...map[string]interface{}) (int64, error) {
-	return UpdatesContext(context.Background(), statement)
+	return std.Updates(statement, args...)
 }
 
 func UpdatesContext(ctx context.Context, statement Statement, args ...map[string]interface{}) (int64, error) {
-	return execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
-		return UpdatesTxContext(ctx, tx, statement)
-	})
+	return std.UpdatesContext(ctx, statement, args...)
 }
 
 func UpdatesTx(tx *sql.Tx, statement Statement, args ...map[string]interface{}) (int64, error) {
-	return UpdateTxContext(context.Background(), tx, statement)
+	return std.UpdatesTx(tx, statement, args...)
 }
 
 func UpdatesTxContext(ctx context.Context, tx *sql.Tx, statement Statement, args ...map[string]interface{}) (int64, error) {
+	return std.UpdatesTxContext(ctx, tx, statement, args...)
+}
+
+func (c *Client) Updates(statement Statement, args ...map[string]interface{}) (int64, error) {
+	return c.UpdatesContext(context.Background(), statement, args...)
+}
+
+func (c *Client) UpdatesContext(ctx context.Context, statement Statement, args ...map[string]interface{}) (int64, error) {
+	return c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		return c.UpdatesTxContext(ctx, tx, statement, args...)
+	})
+}
+
+func (c *Client) UpdatesTx(tx *sql.Tx, statement Statement, args ...map[string]interface{}) (int64, error) {
+	return c.UpdatesTxContext(context.Background(), tx, statement, args...)
+}
+
+func (c *Client) UpdatesTxContext(ctx context.Context, tx *sql.Tx, statement Statement, args ...map[string]interface{}) (int64, error) {
 	defer func(start time.Time) {
 		if statement.skipLog {
 			return
 		}
 		elapsed := time.Now().Sub(start)
-		logger.Infow("xsql - execute update-batch statement", "id", ctx.Value("id"),
+		c.logger.Infow("xsql - execute update-batch statement", "id", ctx.Value("id"),
 			"elapsed_time", elapsed.Milliseconds(),
 			"stmt", statement.RawSql(), "total_item", len(args))
 	}(time.Now())
 	rowsAffected := int64(0)
 	for _, arg := range args {
 		stmt := NewStmt(statement.RawSql()).With(arg)
-		i, err := UpdateTxContext(ctx, tx, stmt.Get())
+		i, err := c.UpdateTxContext(ctx, tx, stmt.Get())
 		if err != nil {
-			_ = tx.Rollback()
 			return 0, err
 		}
 		rowsAffected += i
 	}
 	if statement.expectedRows > 0 {
 		if rowsAffected != statement.expectedRows {
-			_ = tx.Rollback()
 			return 0, ErrWrongNumberAffectedRow
 		}
 	}
 	return int64(rowsAffected), nil
 }
+
+// UpdateModel updates all columns of the record having id of given model.
+// See Client.UpdateModelTxContext
+func UpdateModel(model interface{}) error {
+	return std.UpdateModel(model)
+}
+
+// UpdateModelContext updates all columns of the record having id of given model in a specific context
+func UpdateModelContext(ctx context.Context, model interface{}) error {
+	return std.UpdateModelContext(ctx, model)
+}
+
+// UpdateModelTx updates all columns of the record having id of given model within a transaction
+func UpdateModelTx(tx *sql.Tx, model interface{}) error {
+	return std.UpdateModelTx(tx, model)
+}
+
+// UpdateModelTxContext updates all columns of the record having id of given model
+// within a transaction and a specific context
+func UpdateModelTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
+	return std.UpdateModelTxContext(ctx, tx, model)
+}
+
+// UpdateModel updates all columns of the record having id of given model.
+// See Client.UpdateModelTxContext
+func (c *Client) UpdateModel(model interface{}) error {
+	return c.UpdateModelContext(context.Background(), model)
+}
+
+// UpdateModelContext updates all columns of the record having id of given model in a specific context
+func (c *Client) UpdateModelContext(ctx context.Context, model interface{}) error {
+	_, err := c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		err := c.UpdateModelTxContext(ctx, tx, model)
+		if err != nil {
+			return 0, err
+		}
+		return 1, nil
+	})
+	return err
+}
+
+// UpdateModelTx updates all columns of the record having id of given model within a transaction
+func (c *Client) UpdateModelTx(tx *sql.Tx, model interface{}) error {
+	return c.UpdateModelTxContext(context.Background(), tx, model)
+}
+
+// UpdateModelTxContext updates all columns of the record having id of given model
+// within a transaction and a specific context. Statement is built from the same
+// mapping between column and field which is used by Insert, e.g.
+//
+//	UPDATE <TableName> SET col1 = :col1, col2 = :col2 WHERE id = :id
+//
+// Record is matched by primary key declared in column mapping of model. Columns declared
+// as `readonly` or `noupdate` are not updated. Columns declared as `autoUpdateTime` are
+// set to current time of Clock.
+// If model declares a `version` column, e.g. `column:"version,version"`, the record is
+// updated only if its version is still the version of model, and the version is increased
+// by one in both table and model. If the record is modified by another transaction in the
+// meantime, it returns ErrStaleObject. If the transaction is started by xsql and it is rolled
+// back later, version and timestamps of model are restored, so that a retried transaction uses
+// the same version.
+//
+// If there is no such record, it returns ErrNotFound. If more than one record is
+// updated, it returns ErrWrongNumberAffectedRow
+func (c *Client) UpdateModelTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
+	return c.updateModelTxContext(ctx, tx, model, false)
+}
+
+// UpdateChanged updates columns of given model which are changed since it was loaded.
+// See Client.UpdateChangedTxContext
+func UpdateChanged(model interface{}) error {
+	return std.UpdateChanged(model)
+}
+
+// UpdateChangedContext updates columns of given model which are changed since it was loaded
+// in a specific context
+func UpdateChangedContext(ctx context.Context, model interface{}) error {
+	return std.UpdateChangedContext(ctx, model)
+}
+
+// UpdateChangedTx updates columns of given model which are changed since it was loaded
+// within a transaction
+func UpdateChangedTx(tx *sql.Tx, model interface{}) error {
+	return std.UpdateChangedTx(tx, model)
+}
+
+// UpdateChangedTxContext updates columns of given model which are changed since it was loaded
+// within a transaction and a specific context
+func UpdateChangedTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
+	return std.UpdateChangedTxContext(ctx, tx, model)
+}
+
+// UpdateChanged updates columns of given model which are changed since it was loaded.
+// See Client.UpdateChangedTxContext
+func (c *Client) UpdateChanged(model interface{}) error {
+	return c.UpdateChangedContext(context.Background(), model)
+}
+
+// UpdateChangedContext updates columns of given model which are changed since it was loaded
+// in a specific context
+func (c *Client) UpdateChangedContext(ctx context.Context, model interface{}) error {
+	_, err := c.execTransaction(ctx, func(tx *sql.Tx) (int64, error) {
+		err := c.UpdateChangedTxContext(ctx, tx, model)
+		if err != nil {
+			return 0, err
+		}
+		return 1, nil
+	})
+	return err
+}
+
+// UpdateChangedTx updates columns of given model which are changed since it was loaded
+// within a transaction
+func (c *Client) UpdateChangedTx(tx *sql.Tx, model interface{}) error {
+	return c.UpdateChangedTxContext(context.Background(), tx, model)
+}
+
+// UpdateChangedTxContext updates columns of given model which are changed since it was loaded
+// within a transaction and a specific context. Model must be a pointer to a struct which embeds
+// Tracker. Its snapshot is taken when it is loaded by Query, QueryOne or is saved by xsql.
+//
+// If model does not have a snapshot, all columns are updated as UpdateModelTxContext does.
+// If there is no changed column, no statement is executed. Columns declared as `autoUpdateTime`
+// are not considered as changed, they are updated only along with other changed columns.
+func (c *Client) UpdateChangedTxContext(ctx context.Context, tx *sql.Tx, model interface{}) error {
+	return c.updateModelTxContext(ctx, tx, model, true)
+}
+
+// updateModelTxContext updates columns of the record having id of given model. If changedOnly
+// is true and model has a snapshot, only changed columns are updated
+func (c *Client) updateModelTxContext(ctx context.Context, tx *sql.Tx, model interface{}, changedOnly bool) error {
+	val := reflect.ValueOf(model)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() == reflect.Array || val.Kind() == reflect.Slice {
+		return ErrArgIsArrayOrSlice
+	}
+	pkColumns, pkFields, err := getPrimaryKeys(val.Type())
+	if err != nil {
+		return err
+	}
+	val = addressable(val)
+	tracker := trackerOf(val)
+	if tracker == nil || !tracker.Tracked() {
+		changedOnly = false
+	}
+	tableName := getTableName(val)
+	keys := make(map[string]bool, len(pkColumns))
+	for _, column := range pkColumns {
+		keys[column] = true
+	}
+	values := make([]interface{}, len(pkFields))
+	for i, f := range pkFields {
+		values[i] = f.value(val).Interface()
+	}
+	cond, params := keyCondition(pkColumns, values)
+	version, versioned := getVersion(val.Type())
+	fields := getMapping(val.Type()).fields
+	sets := make([]string, 0, len(fields))
+	var stamped []int
+	for i, f := range fields {
+		if keys[f.column] || !f.options.updatable() || f == version {
+			continue
+		}
+		if f.options.has("autoUpdateTime") {
+			stamped = append(stamped, i)
+			continue
+		}
+		field := f.value(val)
+		if changedOnly && !tracker.changed(f.column, field) {
+			continue
+		}
+		// fields of different embedded structs may have the same name,
+		// so that parameters are named by position of column
+		key := fmt.Sprintf(`xsql_col_%d`, i)
+		params[key] = field.Interface()
+		sets = append(sets, fmt.Sprintf(`%s = :%s`, f.column, key))
+	}
+	if len(sets) == 0 && changedOnly {
+		return nil
+	}
+	// `autoUpdateTime` columns are updated along with changed columns, they are written
+	// into model only after the record is updated
+	stamps := make([]reflect.Value, len(stamped))
+	now := c.clock.Now()
+	for j, i := range stamped {
+		stamps[j] = reflect.New(fields[i].typ).Elem()
+		setTime(stamps[j], now)
+		key := fmt.Sprintf(`xsql_col_%d`, i)
+		params[key] = stamps[j].Interface()
+		sets = append(sets, fmt.Sprintf(`%s = :%s`, fields[i].column, key))
+	}
+	if len(sets) == 0 {
+		return fmt.Errorf(`model %s does not have any column to update`, val.Type())
+	}
+	where := cond
+	if versioned {
+		v := versionOf(version.value(val))
+		params["xsql_version"] = v
+		params["xsql_next_version"] = v + 1
+		sets = append(sets, fmt.Sprintf(`%s = :xsql_next_version`, version.column))
+		where = fmt.Sprintf(`%s AND %s = :xsql_version`, cond, version.column)
+	}
+
+	i, err := c.UpdateTxContext(ctx, tx, NewStmt(`UPDATE`).AppendSql(tableName).
+		AppendSql(`SET`).AppendSql(strings.Join(sets, ", ")).
+		AppendSql(`WHERE`).AppendSql(where).
+		With(params).
+		Get())
+	if err != nil {
+		return err
+	}
+	if i == 0 {
+		if versioned {
+			return c.staleOrNotFound(ctx, tx, tableName, cond, params)
+		}
+		// some drivers, e.g. MySQL, count only rows whose values are changed
+		ok, err := c.exists(ctx, tx, tableName, cond, params)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotFound
+		}
+	} else if i != 1 {
+		return ErrWrongNumberAffectedRow
+	}
+	// model is restored if the transaction is rolled back later,
+	// e.g. in order to be re-run by retry policy
+	if versioned {
+		field := version.value(val)
+		c.restoreOnRollback(tx, field)
+		setInt(field, versionOf(field)+1)
+	}
+	for j, i := range stamped {
+		field := fields[i].value(val)
+		c.restoreOnRollback(tx, field)
+		field.Set(stamps[j])
+	}
+	if tracker != nil {
+		c.restoreSnapshotOnRollback(tx, val)
+		takeSnapshot(val, getMapper(val.Type()))
+	}
+	return nil
+}
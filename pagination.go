@@ -0,0 +1,172 @@
+package xsql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Page is a page of records
+type Page[T any] struct {
+	Items []T
+	// Total is the number of all records fit the statement
+	Total int64
+	// Page is the index of page which is started from 1. It is zero in keyset pagination
+	Page int
+	Size int
+	// NextCursor is values of sort columns of the last record of page, which is
+	// given as Keyset.After for fetching the next page. It is nil if there is no more page
+	NextCursor []interface{}
+}
+
+// Keyset describes a keyset (seek) pagination which fetches records after
+// a specific record instead of skipping a number of records
+type Keyset struct {
+	// Columns are sort columns which must order records uniquely, e.g. ending with primary key
+	Columns []string
+	// Desc sorts records in descending order
+	Desc bool
+	// After is values of Columns of the last record of previous page. It is nil for the first page
+	After []interface{}
+	Size  int
+}
+
+// QueryPage returns records of given page of statement by using the default client.
+// See ClientQueryPage
+func QueryPage[T any](ctx context.Context, statement Statement, page, size int) (Page[T], error) {
+	return ClientQueryPage[T](ctx, std, statement, page, size)
+}
+
+// QueryKeyset returns records after the cursor of keyset by using the default client.
+// See ClientQueryKeyset
+func QueryKeyset[T any](ctx context.Context, statement Statement, keyset Keyset) (Page[T], error) {
+	return ClientQueryKeyset[T](ctx, std, statement, keyset)
+}
+
+// ClientQueryPage returns records of given page of statement which is started from 1,
+// together with total number of records. Limiting clause is appended to statement
+// according to dialect of client, so statement should contain an ORDER BY clause
+func ClientQueryPage[T any](ctx context.Context, c *Client, statement Statement, page, size int) (Page[T], error) {
+	if page < 1 || size < 1 {
+		return Page[T]{}, fmt.Errorf(`page and size must be greater than 0`)
+	}
+	total, err := c.countAll(ctx, statement)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	stmt := NewStmt(statement.RawSql()).
+		AppendSql(paginate(c.dialect, int64(size), int64((page-1)*size))).
+		With(statement.params)
+	stmt.skipLog = statement.skipLog
+	items, err := ClientQueryT[T](ctx, c, stmt.Get())
+	if err != nil {
+		return Page[T]{}, err
+	}
+	return Page[T]{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Size:  size,
+	}, nil
+}
+
+// ClientQueryKeyset returns records of statement which are after Keyset.After in order of
+// Keyset.Columns, together with total number of records. Statement is wrapped as a subquery,
+// so it should not contain an ORDER BY clause
+func ClientQueryKeyset[T any](ctx context.Context, c *Client, statement Statement, keyset Keyset) (Page[T], error) {
+	if keyset.Size < 1 {
+		return Page[T]{}, fmt.Errorf(`size must be greater than 0`)
+	}
+	if len(keyset.Columns) == 0 {
+		return Page[T]{}, fmt.Errorf(`keyset does not have any sort column`)
+	}
+	if keyset.After != nil && len(keyset.After) != len(keyset.Columns) {
+		return Page[T]{}, fmt.Errorf(`number of cursor values and number of sort columns does not match`)
+	}
+	total, err := c.countAll(ctx, statement)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	params := make(map[string]interface{}, len(statement.params)+len(keyset.After))
+	for k, v := range statement.params {
+		params[k] = v
+	}
+	stmt := NewStmt(fmt.Sprintf(`SELECT * FROM (%s) xsql_page`, statement.RawSql()))
+	if keyset.After != nil {
+		op := ">"
+		if keyset.Desc {
+			op = "<"
+		}
+		// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ...
+		conds := make([]string, len(keyset.Columns))
+		for i, column := range keyset.Columns {
+			parts := make([]string, 0, i+1)
+			for j := 0; j < i; j++ {
+				parts = append(parts, fmt.Sprintf(`%s = :xsql_cursor_%d`, keyset.Columns[j], j))
+			}
+			parts = append(parts, fmt.Sprintf(`%s %s :xsql_cursor_%d`, column, op, i))
+			conds[i] = "(" + strings.Join(parts, " AND ") + ")"
+			params[fmt.Sprintf(`xsql_cursor_%d`, i)] = keyset.After[i]
+		}
+		stmt.AppendSql(`WHERE`).AppendSql(strings.Join(conds, " OR "))
+	}
+	orders := make([]string, len(keyset.Columns))
+	for i, column := range keyset.Columns {
+		orders[i] = column
+		if keyset.Desc {
+			orders[i] = column + " DESC"
+		}
+	}
+	stmt.AppendSql(`ORDER BY`).AppendSql(strings.Join(orders, ", ")).
+		AppendSql(paginate(c.dialect, int64(keyset.Size), 0)).
+		With(params)
+	stmt.skipLog = statement.skipLog
+
+	items, err := ClientQueryT[T](ctx, c, stmt.Get())
+	if err != nil {
+		return Page[T]{}, err
+	}
+	p := Page[T]{
+		Items: items,
+		Total: total,
+		Size:  keyset.Size,
+	}
+	if len(items) == keyset.Size {
+		p.NextCursor, err = cursorOf(items[len(items)-1], keyset.Columns)
+		if err != nil {
+			return Page[T]{}, err
+		}
+	}
+	return p, nil
+}
+
+// countAll returns number of records of given statement
+func (c *Client) countAll(ctx context.Context, statement Statement) (int64, error) {
+	stmt := NewStmt(fmt.Sprintf(`SELECT count(*) FROM (%s) xsql_count`, statement.RawSql())).
+		With(statement.params)
+	stmt.skipLog = statement.skipLog
+	return c.CountWithCondContext(ctx, stmt.Get())
+}
+
+// cursorOf returns values of fields of item which are mapped to given columns
+func cursorOf(item interface{}, columns []string) ([]interface{}, error) {
+	val := reflect.ValueOf(item)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	tm := getMapping(val.Type())
+	if tm.err != nil {
+		return nil, tm.err
+	}
+	cursor := make([]interface{}, len(columns))
+	for i, column := range columns {
+		f, ok := tm.byColumn[column]
+		if !ok {
+			return nil, fmt.Errorf(`no such field mapped to column %s`, column)
+		}
+		cursor[i] = f.value(val).Interface()
+	}
+	return cursor, nil
+}
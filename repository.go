@@ -0,0 +1,136 @@
+package xsql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Repository provides standard CRUD actions of a model. The model is a struct
+// which maps its fields to columns by `column` tag and has a primary key, e.g. by
+// embedding BaseModel. Table of model is given by its TableName method.
+//
+// All actions join the transaction carried by context if any. See Client.InTx.
+// If model declares a `softdelete` column, soft-deleted records are excluded from
+// finding, counting and paging unless context is given by IncludeDeleted
+type Repository[T any] struct {
+	c         *Client
+	typ       reflect.Type
+	table     string
+	pkColumns []string
+	selectSql string
+}
+
+// NewRepository creates a repository of model T which uses given client.
+// If client is nil, the default client is used.
+func NewRepository[T any](c *Client) (*Repository[T], error) {
+	var model T
+	val := reflect.ValueOf(&model).Elem()
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(`model %s is not a struct`, val.Type())
+	}
+	pkColumns, _, err := getPrimaryKeys(val.Type())
+	if err != nil {
+		return nil, err
+	}
+	r := &Repository[T]{
+		c:         c,
+		typ:       val.Type(),
+		table:     getTableName(val),
+		pkColumns: pkColumns,
+	}
+	columns, _, err := getColumnsAndFielNames(r.typ)
+	if err != nil {
+		return nil, err
+	}
+	r.selectSql = fmt.Sprintf(`SELECT %s FROM %s`, strings.Join(columns, ","), r.table)
+	return r, nil
+}
+
+// client returns client of repository
+func (r *Repository[T]) client() *Client {
+	if r.c == nil {
+		return std
+	}
+	return r.c
+}
+
+// selectStmt returns the statement which selects all visible records in ctx
+func (r *Repository[T]) selectStmt(ctx context.Context) *Statement {
+	stmt := NewStmt(r.selectSql)
+	if cond := notDeleted(ctx, r.typ); cond != "" {
+		stmt.AppendSql(`WHERE`).AppendSql(cond)
+	}
+	return stmt
+}
+
+// FindByID returns the record having given id. If there is no record, it returns ErrNotFound.
+// If primary key is a composite key, id is a slice of values of key columns
+func (r *Repository[T]) FindByID(ctx context.Context, id interface{}) (T, error) {
+	return ClientFindByIdT[T](ctx, r.client(), id)
+}
+
+// FindAll returns all records in table of model
+func (r *Repository[T]) FindAll(ctx context.Context) ([]T, error) {
+	return ClientQueryT[T](ctx, r.client(), r.selectStmt(ctx).Get())
+}
+
+// Insert adds given model into table
+func (r *Repository[T]) Insert(ctx context.Context, model *T) error {
+	return r.client().InsertContext(ctx, model)
+}
+
+// InsertBatch adds given models into table by batches of batchSize items
+func (r *Repository[T]) InsertBatch(ctx context.Context, models []T, batchSize int) error {
+	return r.client().InsertBatchContext(ctx, models, batchSize)
+}
+
+// Update sets all columns of the record having id of given model.
+// If there is no such record, it returns ErrNotFound
+func (r *Repository[T]) Update(ctx context.Context, model *T) error {
+	return r.client().UpdateModelContext(ctx, model)
+}
+
+// Delete removes the record having id of given model, or marks it as deleted if model
+// declares a `softdelete` column. If there is no such record, it returns ErrNotFound
+func (r *Repository[T]) Delete(ctx context.Context, model *T) error {
+	i, err := r.client().DeleteByIdContext(ctx, model)
+	if err != nil {
+		return err
+	}
+	if i == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Count returns the total records in table
+func (r *Repository[T]) Count(ctx context.Context) (int64, error) {
+	var model T
+	return r.client().CountContext(ctx, model)
+}
+
+// Page returns records of given page which is started from 1. Records are ordered by primary key
+func (r *Repository[T]) Page(ctx context.Context, page, size int) (Page[T], error) {
+	return ClientQueryPage[T](ctx, r.client(), r.selectStmt(ctx).
+		AppendSql(`ORDER BY`).AppendSql(strings.Join(r.pkColumns, ", ")).
+		Get(), page, size)
+}
+
+// PageAfter returns at most size records whose primary key is greater than given id.
+// Records are ordered by primary key. If after is nil, the first page is returned
+func (r *Repository[T]) PageAfter(ctx context.Context, after interface{}, size int) (Page[T], error) {
+	keyset := Keyset{
+		Columns: r.pkColumns,
+		Size:    size,
+	}
+	if after != nil {
+		values, err := keyValues(after, len(r.pkColumns))
+		if err != nil {
+			return Page[T]{}, err
+		}
+		keyset.After = values
+	}
+	return ClientQueryKeyset[T](ctx, r.client(), r.selectStmt(ctx).Get(), keyset)
+}
@@ -0,0 +1,96 @@
+package xsql
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+// next returns the backoff of the retry following the one using given backoff
+func (p *RetryPolicy) next(backoff time.Duration) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	backoff = time.Duration(float64(backoff) * multiplier)
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// IsRetryableError reports whether err is a serialization failure or a deadlock
+// which may succeed if the transaction is re-run. Supported errors are
+//   - Postgres: SQLSTATE 40001 (serialization_failure) and 40P01 (deadlock_detected)
+//   - MySQL: 1213 (ER_LOCK_DEADLOCK) and 1205 (ER_LOCK_WAIT_TIMEOUT)
+//   - SQLite: SQLITE_BUSY
+//
+// Errors of known drivers are detected by their package and shape instead of their types,
+// so that xsql does not depend on any driver. Besides, errors which provide SQLState are
+// checked regardless of their drivers.
+func IsRetryableError(err error) bool {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if isRetryableDriverError(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRetryableDriverError(err error) bool {
+	if e, ok := err.(interface{ SQLState() string }); ok {
+		return isRetryableSQLState(e.SQLState())
+	}
+
+	val := reflect.ValueOf(err)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return false
+		}
+		val = val.Elem()
+	}
+	// fields and codes are only meaningful for errors of drivers, application errors
+	// may have the same shape
+	switch val.Type().PkgPath() {
+	case "modernc.org/sqlite":
+		if e, ok := err.(interface{ Code() int }); ok {
+			return isRetryableSQLiteCode(int64(e.Code()))
+		}
+	case "github.com/go-sql-driver/mysql":
+		if f := structField(val, "Number"); f.IsValid() {
+			switch f.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return f.Uint() == 1213 || f.Uint() == 1205
+			}
+		}
+	case "github.com/lib/pq":
+		if f := structField(val, "Code"); f.IsValid() && f.Kind() == reflect.String {
+			return isRetryableSQLState(f.String())
+		}
+	case "github.com/mattn/go-sqlite3":
+		if f := structField(val, "Code"); f.IsValid() {
+			switch f.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return isRetryableSQLiteCode(f.Int())
+			}
+		}
+	}
+	return false
+}
+
+// structField returns field of given name if val is a struct, otherwise an invalid value
+func structField(val reflect.Value, name string) reflect.Value {
+	if val.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return val.FieldByName(name)
+}
+
+func isRetryableSQLState(state string) bool {
+	return state == "40001" || state == "40P01"
+}
+
+func isRetryableSQLiteCode(code int64) bool {
+	// primary result code is stored in the least significant 8 bits of extended result code
+	return code&0xff == 5
+}
@@ -0,0 +1,91 @@
+package xsql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type sqlStateError struct {
+	state string
+}
+
+func (e *sqlStateError) Error() string {
+	return e.state
+}
+
+func (e *sqlStateError) SQLState() string {
+	return e.state
+}
+
+// appError has the same shape as errors of drivers
+type appError struct {
+	Code   int
+	Number uint16
+}
+
+func (e *appError) Error() string {
+	return fmt.Sprintf("code %d", e.Code)
+}
+
+type appCodeError struct {
+}
+
+func (appCodeError) Error() string {
+	return "busy"
+}
+
+func (appCodeError) Code() int {
+	return 5
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("failed"), false},
+		{"serialization failure", &sqlStateError{"40001"}, true},
+		{"deadlock detected", &sqlStateError{"40P01"}, true},
+		{"unique violation", &sqlStateError{"23505"}, false},
+		{"wrapped serialization failure", fmt.Errorf("insert: %w", &sqlStateError{"40001"}), true},
+		{"nil pointer", (*appError)(nil), false},
+		{"application error with code", &appError{Code: 5}, false},
+		{"application error with number", &appError{Number: 1213}, false},
+		{"application error with code method", appCodeError{}, false},
+		{"ErrStaleObject", ErrStaleObject, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := IsRetryableError(tt.err); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		backoff  time.Duration
+		expected time.Duration
+	}{
+		{"default multiplier", RetryPolicy{}, 10 * time.Millisecond, 20 * time.Millisecond},
+		{"custom multiplier", RetryPolicy{Multiplier: 1.5}, 10 * time.Millisecond, 15 * time.Millisecond},
+		{"negative multiplier", RetryPolicy{Multiplier: -1}, 10 * time.Millisecond, 20 * time.Millisecond},
+		{"below max backoff", RetryPolicy{MaxBackoff: time.Second}, 100 * time.Millisecond, 200 * time.Millisecond},
+		{"capped by max backoff", RetryPolicy{MaxBackoff: 150 * time.Millisecond}, 100 * time.Millisecond, 150 * time.Millisecond},
+		{"zero backoff", RetryPolicy{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.policy.next(tt.backoff); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
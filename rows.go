@@ -0,0 +1,148 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Rows is an iterator over result of a query. Records are read one by one
+// from database instead of being loaded into memory at once
+type Rows struct {
+	ctx       context.Context
+	logger    Logger
+	statement Statement
+	sql       string
+	start     time.Time
+	stmt      *sql.Stmt
+	rows      *sql.Rows
+	cols      []string
+	rm        ResultMapper
+	policy    MappingPolicy
+	count     int
+}
+
+// QueryRows returns an iterator over result of given statement by using the default client.
+// See Client.QueryRows
+func QueryRows(ctx context.Context, statement Statement) (*Rows, error) {
+	return std.QueryRows(ctx, statement)
+}
+
+// QueryRows returns an iterator over result of given statement. It joins the transaction
+// carried by ctx if any, otherwise the statement is executed on database directly.
+// Returned Rows must be closed after using
+func (c *Client) QueryRows(ctx context.Context, statement Statement) (*Rows, error) {
+	r := &Rows{
+		ctx:       ctx,
+		logger:    c.logger,
+		statement: statement,
+		sql:       statement.build(c.dialect),
+		start:     time.Now(),
+		policy:    c.mappingPolicy,
+	}
+	stmt, err := c.preparer(ctx).PrepareContext(ctx, r.sql)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, statement.GetParams()...)
+	if err != nil {
+		_ = stmt.Close()
+		return nil, err
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		_ = stmt.Close()
+		return nil, err
+	}
+	r.stmt = stmt
+	r.rows = rows
+	r.cols = cols
+	return r, nil
+}
+
+// Next prepares the next record for reading by Scan. It returns false
+// if there is no more record or an error happened, see Err
+func (r *Rows) Next() bool {
+	return r.rows.Next()
+}
+
+// Scan copies columns of current record into dest which must be a pointer.
+// See Client.QueryTxContext for supported types of dest
+func (r *Rows) Scan(dest interface{}) error {
+	val := reflect.ValueOf(dest)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return fmt.Errorf("destination is not a pointer")
+	}
+	elem := val.Elem()
+	if r.rm.Type != elem.Type() {
+		r.rm = getMapper(elem.Type())
+		r.rm.policy = r.policy
+	}
+	err := scanRow(r.rows, r.cols, r.rm, elem)
+	if err != nil {
+		return err
+	}
+	r.count++
+	return nil
+}
+
+// Err returns the error, if any, that was encountered during iteration
+func (r *Rows) Err() error {
+	return r.rows.Err()
+}
+
+// Close stops iteration and releases resources of Rows
+func (r *Rows) Close() error {
+	defer func() {
+		if r.statement.skipLog {
+			return
+		}
+		elapsed := time.Since(r.start)
+		r.logger.Infow("xsql - execute streaming query statement", "id", r.ctx.Value("id"),
+			"elapsed_time", elapsed.Milliseconds(),
+			"stmt", r.sql, "params", r.statement.params, "total_item", r.count)
+	}()
+	err := r.rows.Close()
+	_ = r.stmt.Close()
+	return err
+}
+
+// QueryEach calls fn for each record of given statement by using the default client.
+// See ClientQueryEach
+func QueryEach[T any](ctx context.Context, statement Statement, fn func(row T) error) error {
+	return ClientQueryEach[T](ctx, std, statement, fn)
+}
+
+// ClientQueryEach calls fn for each record of given statement without loading all
+// records into memory. Iteration stops when fn returns an error, which is returned by
+// ClientQueryEach unless it is ErrStop.
+func ClientQueryEach[T any](ctx context.Context, c *Client, statement Statement, fn func(row T) error) error {
+	return c.queryTransaction(ctx, func(tx *sql.Tx) error {
+		rows, err := c.QueryRows(c.ContextWithTx(ctx, tx), statement)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+		for rows.Next() {
+			var row T
+			err = rows.Scan(&row)
+			if err != nil {
+				return err
+			}
+			err = fn(row)
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+}
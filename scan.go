@@ -0,0 +1,86 @@
+package xsql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// RowScanner is implemented by models which decode a record by themselves instead of
+// being mapped by `column` tag, e.g.
+//
+//	func (s *Summary) ScanRow(rows *sql.Rows) error {
+//		return rows.Scan(&s.Total, &s.Average)
+//	}
+type RowScanner interface {
+	ScanRow(rows *sql.Rows) error
+}
+
+var (
+	rowScannerType = reflect.TypeOf((*RowScanner)(nil)).Elem()
+	sqlScannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isModel reports whether values of given type are mapped column by column into fields
+func isModel(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PtrTo(t).Implements(sqlScannerType)
+}
+
+// scanRow copies current record of rows into elem which must be addressable. Depending on
+// type of elem, the record is
+//   - decoded by ScanRow method if elem implements RowScanner
+//   - mapped into fields by `column` tag if elem is a struct
+//   - stored as column-value pairs if elem is a map with string key
+//   - scanned as a single value otherwise, e.g. int64, string, time.Time or sql.NullString
+//
+// Pointer is allocated if it is nil, then the record is copied into the value it points to
+func scanRow(rows *sql.Rows, cols []string, rm ResultMapper, elem reflect.Value) error {
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+		return scanRow(rows, cols, rm, elem.Elem())
+	}
+	if elem.Addr().Type().Implements(rowScannerType) {
+		return elem.Addr().Interface().(RowScanner).ScanRow(rows)
+	}
+	switch {
+	case isModel(elem.Type()):
+		args, err := rm.scanArgs(elem, cols)
+		if err != nil {
+			return err
+		}
+		err = rows.Scan(args...)
+		if err != nil {
+			return err
+		}
+		takeSnapshot(elem, rm)
+		return nil
+	case elem.Kind() == reflect.Map:
+		if elem.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf(`key of map %s is not string`, elem.Type())
+		}
+		values := make([]reflect.Value, len(cols))
+		args := make([]interface{}, len(cols))
+		for i := range cols {
+			values[i] = reflect.New(elem.Type().Elem())
+			args[i] = values[i].Interface()
+		}
+		err := rows.Scan(args...)
+		if err != nil {
+			return err
+		}
+		if elem.IsNil() {
+			elem.Set(reflect.MakeMapWithSize(elem.Type(), len(cols)))
+		}
+		for i, col := range cols {
+			elem.SetMapIndex(reflect.ValueOf(col).Convert(elem.Type().Key()), values[i].Elem())
+		}
+		return nil
+	default:
+		if len(cols) != 1 {
+			return fmt.Errorf(`can not scan %d columns into %s`, len(cols), elem.Type())
+		}
+		return rows.Scan(elem.Addr().Interface())
+	}
+}
@@ -0,0 +1,60 @@
+package xsql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// includeDeletedKey is the key of soft-delete visibility stored in context.Context
+type includeDeletedKey struct{}
+
+// IncludeDeleted returns a copy of ctx with which soft-deleted records are visible to
+// Count, FindById and Repository, e.g.
+//
+//	n, err := xsql.CountContext(xsql.IncludeDeleted(ctx), ExampleTable{})
+func IncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// includeDeleted reports whether soft-deleted records are visible in ctx
+func includeDeleted(ctx context.Context) bool {
+	v, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return v
+}
+
+// getSoftDelete returns field of reflect.Type which is declared as `softdelete`,
+// e.g. `column:"deleted_at,softdelete"`
+func getSoftDelete(t reflect.Type) (*fieldMapping, bool) {
+	f := getMapping(t).softDelete
+	return f, f != nil
+}
+
+// notDeleted returns the condition which excludes soft-deleted records of reflect.Type
+// unless they are visible in ctx. It returns empty string if there is no such condition
+func notDeleted(ctx context.Context, t reflect.Type) string {
+	if includeDeleted(ctx) {
+		return ""
+	}
+	f, ok := getSoftDelete(t)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(`%s IS NULL`, f.column)
+}
+
+// isTimeType reports whether given type can be set by setTime
+func isTimeType(t reflect.Type) bool {
+	return t == timeType || (t.Kind() == reflect.Ptr && t.Elem() == timeType)
+}
+
+// setTime sets given time into a field of either time.Time or *time.Time type
+func setTime(field reflect.Value, now time.Time) {
+	switch {
+	case field.Type() == timeType:
+		field.Set(reflect.ValueOf(now))
+	case field.Kind() == reflect.Ptr && field.Type().Elem() == timeType:
+		field.Set(reflect.ValueOf(&now))
+	}
+}
@@ -11,7 +11,6 @@ type Statement struct {
 	lastRune     rune
 	expectedRows int64
 	params       map[string]interface{}
-	finalString  string
 	args         []interface{}
 	skipLog      bool
 }
@@ -74,16 +73,22 @@ func (s *Statement) GetParams() []interface{} {
 	return rs
 }
 
+// String returns the final sql statement using dialect of the default client
 func (s *Statement) String() string {
-	if s.finalString != "" {
-		return s.finalString
-	}
-	s.finalString = s.b.String()
+	return s.build(std.dialect)
+}
+
+// build replaces name place holders by parameter place holders of given dialect
+func (s *Statement) build(d Dialect) string {
+	// statement is built again for each dialect, so that the same statement
+	// can be executed by clients of different databases
+	s.args = nil
+	query := s.b.String()
 	if s.params == nil || len(s.params) == 0 {
-		return s.finalString
+		return query
 	}
 	numberOfValues := 0
-	runes := []rune(s.finalString)
+	runes := []rune(query)
 	var b strings.Builder
 	for i := 0; i < len(runes); i++ {
 		r := runes[i]
@@ -120,8 +125,7 @@ func (s *Statement) String() string {
 		}
 		b.WriteRune(r)
 	}
-	s.finalString = fmt.Sprintf(b.String(), strToIntf(dialect.Parameterizie(numberOfValues))...)
-	return s.finalString
+	return fmt.Sprintf(b.String(), strToIntf(d.Parameterizie(numberOfValues))...)
 }
 
 func getParamLen(p interface{}) int {
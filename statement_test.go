@@ -0,0 +1,48 @@
+package xsql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatementBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		expected string
+	}{
+		{"postgres", PostgreDialect{}, `SELECT * FROM item WHERE id IN ($1,$2,$3) AND name = $4`},
+		{"mysql", MySQLDialect{}, `SELECT * FROM item WHERE id IN (?,?,?) AND name = ?`},
+		{"oracle", OracleDialect{}, `SELECT * FROM item WHERE id IN (:1,:2,:3) AND name = :4`},
+		// the same statement is built again by the first dialect
+		{"postgres again", PostgreDialect{}, `SELECT * FROM item WHERE id IN ($1,$2,$3) AND name = $4`},
+	}
+	// the same statement is shared by clients of different databases
+	stmt := NewStmt(`SELECT * FROM item WHERE id IN (:ids) AND name = :name`).With(map[string]interface{}{
+		"ids":  []int{1, 2, 3},
+		"name": "item",
+	}).Get()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := stmt.build(tt.dialect); actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+			expectedParams := []interface{}{1, 2, 3, "item"}
+			if params := stmt.GetParams(); !reflect.DeepEqual(params, expectedParams) {
+				t.Errorf("expected params %v, got %v", expectedParams, params)
+			}
+		})
+	}
+}
+
+func TestStatementBuildWithoutParams(t *testing.T) {
+	stmt := NewStmt(`SELECT * FROM item WHERE created > '2020-01-01 00:00:00'`).Get()
+	for _, d := range []Dialect{PostgreDialect{}, MySQLDialect{}} {
+		if actual := stmt.build(d); actual != stmt.RawSql() {
+			t.Errorf("expected %s, got %s", stmt.RawSql(), actual)
+		}
+		if params := stmt.GetParams(); len(params) != 0 {
+			t.Errorf("expected no params, got %v", params)
+		}
+	}
+}
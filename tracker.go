@@ -0,0 +1,105 @@
+package xsql
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+// Tracker keeps a snapshot of column values of a model when it is loaded by Query
+// or QueryOne, so that UpdateChanged only sets columns which are changed since then.
+// It is enabled by embedding Tracker into model, e.g.
+//
+//	type ExampleTable struct {
+//		xsql.BaseModel `column:"__embedded"`
+//		xsql.Tracker
+//		Text *string `column:"text"`
+//	}
+type Tracker struct {
+	snapshot map[string]interface{}
+}
+
+// trackable is implemented by models which embed Tracker
+type trackable interface {
+	tracker() *Tracker
+}
+
+var trackerType = reflect.TypeOf(Tracker{})
+
+func (t *Tracker) tracker() *Tracker {
+	return t
+}
+
+// Tracked reports whether a snapshot of model is taken
+func (t *Tracker) Tracked() bool {
+	return t.snapshot != nil
+}
+
+// changed reports whether value of given column is different from its snapshot
+func (t *Tracker) changed(column string, v reflect.Value) bool {
+	old, ok := t.snapshot[column]
+	if !ok {
+		return true
+	}
+	return !reflect.DeepEqual(old, snapshotValue(v))
+}
+
+// trackerOf returns Tracker of given struct value if any
+func trackerOf(val reflect.Value) *Tracker {
+	if !val.CanAddr() {
+		return nil
+	}
+	t, ok := val.Addr().Interface().(trackable)
+	if !ok {
+		return nil
+	}
+	return t.tracker()
+}
+
+// takeSnapshot stores current column values of given struct value into its Tracker
+func takeSnapshot(val reflect.Value, rm ResultMapper) {
+	t := trackerOf(val)
+	if t == nil {
+		return
+	}
+	tm := rm.typeMapping()
+	snapshot := make(map[string]interface{}, len(tm.fields))
+	for _, f := range tm.fields {
+		snapshot[f.column] = snapshotValue(f.value(val))
+	}
+	t.snapshot = snapshot
+}
+
+// restoreSnapshotOnRollback keeps current snapshot of model, which is going to be replaced
+// within tx, and restores it when tx is rolled back. See Client.restoreOnRollback
+func (c *Client) restoreSnapshotOnRollback(tx *sql.Tx, val reflect.Value) {
+	t := trackerOf(val)
+	if t == nil {
+		return
+	}
+	old := t.snapshot
+	_ = c.OnRollback(tx, func() {
+		t.snapshot = old
+	})
+}
+
+// snapshotValue returns a copy of value of field, so that later changes made
+// through pointer or slice do not affect the snapshot
+func snapshotValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		cp := reflect.New(v.Type().Elem())
+		cp.Elem().Set(v.Elem())
+		return cp.Interface()
+	case reflect.Slice:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(cp, v)
+		return cp.Interface()
+	}
+	return v.Interface()
+}
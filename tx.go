@@ -0,0 +1,207 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// WithTx executes txFunc within a new transaction of the default client.
+// See Client.WithTx
+func WithTx(ctx context.Context, opts *sql.TxOptions, txFunc func(*sql.Tx) error) error {
+	return std.WithTx(ctx, opts, txFunc)
+}
+
+// WithTx executes txFunc within a new transaction. The transaction is committed
+// if txFunc returns nil, otherwise it is rolled back. If txFunc panics, the transaction
+// is rolled back and the panic is re-thrown.
+//
+// If opts is nil, the isolation level of DbOption is used.
+//
+// WithTx always starts a new transaction, even if ctx carries one. Use InTx
+// in order to join the transaction carried by ctx.
+func (c *Client) WithTx(ctx context.Context, opts *sql.TxOptions, txFunc func(*sql.Tx) error) error {
+	if opts == nil {
+		opts = &sql.TxOptions{
+			Isolation: c.isoLevel,
+			ReadOnly:  false,
+		}
+	}
+	return c.runTx(ctx, opts, txFunc)
+}
+
+// WithSavepoint executes txFunc as a nested unit of work of given transaction
+// of the default client. See Client.WithSavepoint
+func WithSavepoint(ctx context.Context, tx *sql.Tx, txFunc func(*sql.Tx) error) error {
+	return std.WithSavepoint(ctx, tx, txFunc)
+}
+
+// WithSavepoint executes txFunc as a nested unit of work of given transaction.
+// A savepoint is created before calling txFunc. If txFunc returns an error or panics,
+// the transaction is rolled back to that savepoint only, so that the outer transaction
+// is still usable. Otherwise, the savepoint is released.
+func (c *Client) WithSavepoint(ctx context.Context, tx *sql.Tx, txFunc func(*sql.Tx) error) (err error) {
+	name := fmt.Sprintf(`xsql_sp_%d`, atomic.AddUint64(&c.savepointSeq, 1))
+	_, err = tx.ExecContext(ctx, savepoint(c.dialect, name))
+	if err != nil {
+		return
+	}
+	h, _ := c.txHooks(tx)
+	mark := h.mark()
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = tx.ExecContext(ctx, rollbackToSavepoint(c.dialect, name))
+			h.rollbackTo(mark)
+			panic(p) // re-throw panic after rolling back to savepoint
+		} else if err != nil {
+			_, _ = tx.ExecContext(ctx, rollbackToSavepoint(c.dialect, name))
+			h.rollbackTo(mark)
+		} else if release := releaseSavepoint(c.dialect, name); release != "" {
+			_, err = tx.ExecContext(ctx, release)
+		}
+	}()
+	return txFunc(tx)
+}
+
+// txContextKey is the key of active transaction stored in context.Context
+type txContextKey struct{}
+
+// txContext is the active transaction stored in context.Context
+type txContext struct {
+	client *Client
+	tx     *sql.Tx
+}
+
+// ContextWithTx returns a copy of ctx which carries tx of the default client.
+// See Client.ContextWithTx
+func ContextWithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return std.ContextWithTx(ctx, tx)
+}
+
+// ContextWithTx returns a copy of ctx which carries tx. Functions of client which
+// are called with returned context join tx instead of starting a new transaction.
+// Committing or rolling back tx is still the responsibility of caller.
+func (c *Client) ContextWithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, txContext{
+		client: c,
+		tx:     tx,
+	})
+}
+
+// TxFromContext returns transaction of the default client carried by ctx
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	return std.TxFromContext(ctx)
+}
+
+// TxFromContext returns transaction of client carried by ctx
+func (c *Client) TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	v, ok := ctx.Value(txContextKey{}).(txContext)
+	if !ok || v.client != c || v.tx == nil {
+		return nil, false
+	}
+	return v.tx, true
+}
+
+// preparer is implemented by both *sql.DB and *sql.Tx
+type preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// preparer returns transaction carried by ctx if any. Otherwise, it returns the database
+func (c *Client) preparer(ctx context.Context) preparer {
+	if tx, ok := c.TxFromContext(ctx); ok {
+		return tx
+	}
+	return c.db
+}
+
+// InTx executes txFunc within a transaction of the default client. See Client.InTx
+func InTx(ctx context.Context, opts *sql.TxOptions, txFunc func(ctx context.Context) error) error {
+	return std.InTx(ctx, opts, txFunc)
+}
+
+// InTx executes txFunc with a context which carries the active transaction.
+// If ctx already carries a transaction of client, txFunc joins it. Otherwise
+// a new transaction is started as WithTx does.
+//
+// Every xxxContext function of client called with the context given to txFunc,
+// e.g. InsertContext or QueryContext, runs within that transaction.
+func (c *Client) InTx(ctx context.Context, opts *sql.TxOptions, txFunc func(ctx context.Context) error) error {
+	if _, ok := c.TxFromContext(ctx); ok {
+		return txFunc(ctx)
+	}
+	return c.WithTx(ctx, opts, func(tx *sql.Tx) error {
+		return txFunc(c.ContextWithTx(ctx, tx))
+	})
+}
+
+// joinTx executes txFunc within the transaction carried by ctx without committing
+// or rolling back it. If ctx does not carry any transaction of client, a new one
+// is started with given options, see runTx
+func (c *Client) joinTx(ctx context.Context, opts *sql.TxOptions, txFunc func(*sql.Tx) error) error {
+	if tx, ok := c.TxFromContext(ctx); ok {
+		return txFunc(tx)
+	}
+	return c.runTx(ctx, opts, txFunc)
+}
+
+// runTx begins a transaction with given options then commits or rolls back it
+// depending on result of txFunc. The whole transaction is re-run according to
+// retry policy of client if it fails with a retryable error.
+func (c *Client) runTx(ctx context.Context, opts *sql.TxOptions, txFunc func(*sql.Tx) error) error {
+	policy := c.retry
+	if policy == nil || policy.MaxAttempts <= 1 {
+		return c.runTxOnce(ctx, opts, txFunc)
+	}
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryableError
+	}
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.runTxOnce(ctx, opts, txFunc)
+		if err == nil || attempt >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+		c.logger.Warnw("xsql - retry transaction", "id", ctx.Value("id"),
+			"attempt", attempt, "backoff", backoff.Milliseconds(), "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = policy.next(backoff)
+	}
+}
+
+// runTxOnce executes txFunc within a transaction exactly one time
+func (c *Client) runTxOnce(ctx context.Context, opts *sql.TxOptions, txFunc func(*sql.Tx) error) (err error) {
+	tx, err := c.db.BeginTx(ctx, opts)
+	if err != nil {
+		return
+	}
+	h := &txHooks{}
+	c.hooks.Store(tx, h)
+	defer func() {
+		c.hooks.Delete(tx)
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			h.fireRollback()
+			panic(p) // re-throw panic after Rollback
+		} else if err != nil {
+			_ = tx.Rollback() // err is non-nil; don't change it
+			h.fireRollback()
+		} else {
+			err = tx.Commit() // err is nil; if Commit returns error update err
+			if err != nil {
+				_ = tx.Rollback()
+				h.fireRollback()
+			} else {
+				h.fireCommit()
+			}
+		}
+	}()
+	return txFunc(tx)
+}
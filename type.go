@@ -13,16 +13,83 @@ var (
 	ErrWrongNumberInserted    = fmt.Errorf(`number of inserted recods is smaller than expectation`)
 	ErrArgNotArrayAndSlice    = fmt.Errorf(`given argument is neither array nor slice`)
 	ErrArgIsArrayOrSlice      = fmt.Errorf(`given argument is either array or slice`)
+	ErrTxNotManaged           = fmt.Errorf(`transaction is not started by xsql`)
+	ErrStop                   = fmt.Errorf(`stop iteration`)
+	ErrStaleObject            = fmt.Errorf(`record is modified or deleted by another transaction`)
 )
 
 type Dialect interface {
 	Parameterizie(numberOfValue int) []string
 }
 
+// The following interfaces are optionally implemented by a Dialect whose SQL differs from
+// the default one. Built-in dialects implement all of them. If a custom dialect does not
+// implement an interface, the default SQL documented on the interface is used.
+
+// SavepointDialect builds statements of savepoints.
+// Default statements are `SAVEPOINT name`, `RELEASE SAVEPOINT name` and `ROLLBACK TO SAVEPOINT name`
+type SavepointDialect interface {
+	// Savepoint returns statement which creates a savepoint with given name
+	Savepoint(name string) string
+
+	// ReleaseSavepoint returns statement which releases a savepoint with given name.
+	// An empty string means that the database does not support releasing savepoint
+	ReleaseSavepoint(name string) string
+
+	// RollbackToSavepoint returns statement which rolls back to a savepoint with given name
+	RollbackToSavepoint(name string) string
+}
+
+// PaginationDialect builds clause which limits result of a query.
+// Default clause is `LIMIT limit OFFSET offset`
+type PaginationDialect interface {
+	// Paginate returns clause which limits result of a query
+	Paginate(limit, offset int64) string
+}
+
+// KeyReturningDialect tells how keys generated by database are read.
+// By default, generated keys are not read
+type KeyReturningDialect interface {
+	// KeyReturning returns the way of reading keys which are generated by database
+	// after an insert statement
+	KeyReturning() KeyReturning
+}
+
+// SequenceDialect builds statement which selects next value of a sequence.
+// By default, sequence is not supported
+type SequenceDialect interface {
+	// NextSequenceValue returns statement which selects next value of given sequence.
+	// An empty string means that the database does not support sequence
+	NextSequenceValue(name string) string
+}
+
+// UpsertDialect builds upsert statement.
+// Default statement is `INSERT ... ON CONFLICT (...) DO UPDATE SET c = EXCLUDED.c`
+type UpsertDialect interface {
+	// Upsert returns statement which inserts given number of rows into table, or updates
+	// updateColumns of existing rows which conflict on conflictColumns. Parameters of
+	// statement are values of columns of each row in order
+	Upsert(table string, columns, conflictColumns, updateColumns []string, rows int) string
+}
+
+// KeyReturning is the way of reading keys which are generated by database after an insert statement
+type KeyReturning int
+
+const (
+	// ReturningNone means that generated keys can not be read
+	ReturningNone KeyReturning = iota
+	// ReturningClause reads generated keys by `INSERT ... RETURNING id`
+	ReturningClause
+	// ReturningLastInsertId reads generated keys by sql.Result.LastInsertId
+	ReturningLastInsertId
+	// ReturningInto reads generated key by `INSERT ... RETURNING id INTO :n`
+	ReturningInto
+)
+
 type BaseModel struct {
-	Id      int64     `column:"id"`
-	Created time.Time `column:"created"`
-	Updated time.Time `column:"updated"`
+	Id      int64     `column:"id,pk"`
+	Created time.Time `column:"created,autoCreateTime"`
+	Updated time.Time `column:"updated,autoUpdateTime"`
 }
 
 type DbOption struct {
@@ -35,14 +102,51 @@ type DbOption struct {
 	MaxLifeTime  time.Duration
 	IsoLevel     sql.IsolationLevel
 	ReadOnly     bool
+	Retry        *RetryPolicy
+	Clock        Clock
+	IDGenerator  IDGenerator
+	Mapping      MappingPolicy
 	Dialect
 	Logger
 }
 
+// RetryPolicy describes how a transaction is re-run when database reports
+// a serialization failure or a deadlock
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one
+	MaxAttempts int
+	// InitialBackoff is the waiting time before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper bound of waiting time. Zero means no limit
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which backoff grows after each retry. Default is 2
+	Multiplier float64
+	// Retryable reports whether given error is retryable. If nil, IsRetryableError is used
+	Retryable func(error) bool
+}
+
+// MappingPolicy describes how columns of query result are matched with fields of struct.
+// Policies can be combined, e.g. MappingIgnoreUnknown | MappingCaseInsensitive
+type MappingPolicy int
+
+const (
+	// MappingStrict requires every column to be mapped to a field by its exact name.
+	// It is the default policy
+	MappingStrict MappingPolicy = 0
+	// MappingIgnoreUnknown skips columns which are not mapped to any field
+	MappingIgnoreUnknown MappingPolicy = 1
+	// MappingCaseInsensitive matches columns with fields regardless of case,
+	// e.g. upper-case columns returned by Oracle. Names are not normalized otherwise,
+	// so column USERID does not match field tagged `user_id`
+	MappingCaseInsensitive MappingPolicy = 2
+)
+
 type ResultMapper struct {
 	reflect.Type
 	Col2Field map[string]string
 	Field2Col map[string]string
+	mapping   *typeMapping
+	policy    MappingPolicy
 }
 
 type Logger interface {
@@ -0,0 +1,37 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+)
+
+// getVersion returns field of reflect.Type which is declared as `version`,
+// e.g. `column:"version,version"`. Field of version must be an integer
+func getVersion(t reflect.Type) (*fieldMapping, bool) {
+	f := getMapping(t).version
+	return f, f != nil
+}
+
+// versionOf returns current value of version field
+func versionOf(field reflect.Value) int64 {
+	switch field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(field.Uint())
+	default:
+		return field.Int()
+	}
+}
+
+// staleOrNotFound tells why no record matched condition of key together with version.
+// It returns ErrStaleObject if the record matching cond still exists, otherwise ErrNotFound
+func (c *Client) staleOrNotFound(ctx context.Context, tx *sql.Tx, tableName, cond string, params map[string]interface{}) error {
+	ok, err := c.exists(ctx, tx, tableName, cond, params)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return ErrStaleObject
+	}
+	return ErrNotFound
+}
@@ -4,64 +4,136 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 )
 
-var (
-	dialect Dialect
+// Client wraps a database connection together with its dialect, logger and
+// transaction options. Each client is independent, so one process may talk to
+// many databases at the same time.
+type Client struct {
 	db      *sql.DB
+	dialect Dialect
 	logger  Logger
+	clock   Clock
 
-	isoLevel sql.IsolationLevel = sql.LevelDefault
-	readOnly bool               = false
-)
+	idGenerator IDGenerator
 
-func BeginTx() (*sql.Tx, error) {
-	return db.Begin()
+	mappingPolicy MappingPolicy
+
+	isoLevel sql.IsolationLevel
+	readOnly bool
+	retry    *RetryPolicy
+
+	// savepointSeq is used for generating name of savepoints
+	savepointSeq uint64
+
+	// hooks keeps callbacks of transactions started by client
+	hooks sync.Map
 }
 
-func BeginTxContext(ctx context.Context) (*sql.Tx, error) {
-	return db.BeginTx(ctx, nil)
+// std is the client used by package-level functions. It is replaced by Open
+var std = &Client{
+	logger:   DefaultLogger{},
+	clock:    SystemClock{},
+	isoLevel: sql.LevelDefault,
 }
 
-func Open(opt DbOption) error {
+// New creates a new client from given option
+func New(opt DbOption) (*Client, error) {
 	var err error
+	c := &Client{}
 	if opt.DB != nil {
 		//it is configured from outside
-		db = opt.DB
+		c.db = opt.DB
 	} else {
-		db, err = sql.Open(opt.Driver, opt.DSN)
+		c.db, err = sql.Open(opt.Driver, opt.DSN)
 		if err != nil {
-			return fmt.Errorf(`failed to open db connection %v`, err)
+			return nil, fmt.Errorf(`failed to open db connection %v`, err)
 		}
-		db.SetMaxOpenConns(opt.MaxOpenConns)
-		db.SetMaxIdleConns(opt.MaxIdleConns)
-		db.SetConnMaxIdleTime(opt.MaxIdleTime)
-		db.SetConnMaxLifetime(opt.MaxLifeTime)
+		c.db.SetMaxOpenConns(opt.MaxOpenConns)
+		c.db.SetMaxIdleConns(opt.MaxIdleConns)
+		c.db.SetConnMaxIdleTime(opt.MaxIdleTime)
+		c.db.SetConnMaxLifetime(opt.MaxLifeTime)
 	}
 
-	dialect = opt.Dialect
+	c.dialect = opt.Dialect
 	if opt.Dialect == nil {
-		dialect, err = getDbDialect(opt.Driver)
+		c.dialect, err = getDbDialect(opt.Driver)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	isoLevel = opt.IsoLevel
-	readOnly = opt.ReadOnly
-	if dialect == nil {
-		return fmt.Errorf(`db dialect is not configured`)
+	c.isoLevel = opt.IsoLevel
+	c.readOnly = opt.ReadOnly
+	c.retry = opt.Retry
+	if c.dialect == nil {
+		return nil, fmt.Errorf(`db dialect is not configured`)
 	}
 	if opt.Logger == nil {
-		logger = DefaultLogger{}
+		c.logger = DefaultLogger{}
 	} else {
-		logger = opt.Logger
+		c.logger = opt.Logger
 	}
+	c.idGenerator = opt.IDGenerator
+	c.mappingPolicy = opt.Mapping
+	if opt.Clock == nil {
+		c.clock = SystemClock{}
+	} else {
+		c.clock = opt.Clock
+	}
+	return c, nil
+}
+
+// Open initiates the default client which is used by package-level functions
+func Open(opt DbOption) error {
+	c, err := New(opt)
+	if err != nil {
+		return err
+	}
+	std = c
 	return nil
 }
 
+// Default returns the client used by package-level functions
+func Default() *Client {
+	return std
+}
+
+// Close closes the default client
 func Close() error {
-	if db == nil {
+	return std.Close()
+}
+
+// Close closes the underlying database connection of client
+func (c *Client) Close() error {
+	if c.db == nil {
 		return fmt.Errorf(`db is not initiated`)
 	}
-	return db.Close()
+	return c.db.Close()
+}
+
+// DB returns the underlying *sql.DB of client
+func (c *Client) DB() *sql.DB {
+	return c.db
+}
+
+// Dialect returns the dialect of client
+func (c *Client) Dialect() Dialect {
+	return c.dialect
+}
+
+func BeginTx() (*sql.Tx, error) {
+	return std.BeginTx()
+}
+
+func BeginTxContext(ctx context.Context) (*sql.Tx, error) {
+	return std.BeginTxContext(ctx)
+}
+
+func (c *Client) BeginTx() (*sql.Tx, error) {
+	return c.db.Begin()
+}
+
+func (c *Client) BeginTxContext(ctx context.Context) (*sql.Tx, error) {
+	return c.db.BeginTx(ctx, nil)
 }